@@ -4,8 +4,10 @@ go 1.22.0
 
 require (
 	github.com/fatih/color v1.16.0
+	github.com/klauspost/compress v1.18.0
 	github.com/olebedev/when v1.0.0
 	github.com/stretchr/testify v1.3.0
+	golang.org/x/term v0.14.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -0,0 +1,327 @@
+package logs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	cacheClearCommandName = "cache clear"
+
+	cacheDirName        = "swo-cli"
+	defaultCacheMaxSize = 100 << 20
+
+	// cacheDelay is how long after its max-time a query is cached, so that
+	// logs ingested late are not missing from the cached results.
+	cacheDelay = 15 * time.Minute
+)
+
+var (
+	errCacheMaxSizeFlag = errors.New("failed to parse cache-max-size")
+	errCacheArgs        = errors.New("cache clear takes no arguments")
+)
+
+// resultCache stores pages of searches whose time range is entirely in the
+// past, since their results can no longer change.
+type resultCache struct {
+	dir     string
+	maxSize int64
+}
+
+// cacheKey is the normalized form of a request. Hashing the token keeps
+// results of different organizations apart without storing the token.
+type cacheKey struct {
+	ApiUrl    string `json:"apiUrl"`
+	Token     string `json:"token"`
+	Filter    string `json:"filter"`
+	Group     string `json:"group"`
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+	PageSize  int    `json:"pageSize"`
+	Cursor    string `json:"cursor"`
+}
+
+// defaultCacheDir returns $XDG_CACHE_HOME/swo-cli or the platform
+// equivalent.
+func defaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, cacheDirName), nil
+}
+
+func newResultCache(maxSize int64) (*resultCache, error) {
+	dir, err := defaultCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	if maxSize <= 0 {
+		maxSize = defaultCacheMaxSize
+	}
+
+	return &resultCache{dir: dir, maxSize: maxSize}, nil
+}
+
+// cacheable reports whether results of q are final, i.e. its max-time has
+// passed by more than cacheDelay. Pages fetched by cursor keep the fields of
+// the first query.
+func cacheable(q Query) bool {
+	return !q.EndTime.IsZero() && q.EndTime.Before(now.Add(-cacheDelay))
+}
+
+func (c *resultCache) path(apiUrl, token string, q Query) (string, error) {
+	tokenHash := sha256.Sum256([]byte(token))
+	key := cacheKey{
+		ApiUrl:   strings.TrimSuffix(apiUrl, "/"),
+		Token:    hex.EncodeToString(tokenHash[:]),
+		Filter:   strings.Join(strings.Fields(q.Filter), " "),
+		Group:    q.Group,
+		EndTime:  q.EndTime.UTC().Format(time.RFC3339),
+		PageSize: q.PageSize,
+		Cursor:   q.Cursor,
+	}
+	if !q.StartTime.IsZero() {
+		key.StartTime = q.StartTime.UTC().Format(time.RFC3339)
+	}
+
+	content, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(content)
+
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json"), nil
+}
+
+func (c *resultCache) get(path string) (*LogsData, bool) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var logs LogsData
+	if err := json.Unmarshal(content, &logs); err != nil {
+		slog.Debug("Ignoring corrupted cache entry", slog.String("path", path), slog.String("error", err.Error()))
+		return nil, false
+	}
+
+	// mark the entry as recently used for eviction
+	current := time.Now()
+	_ = os.Chtimes(path, current, current)
+
+	return &logs, true
+}
+
+func (c *resultCache) put(path string, logs *LogsData) error {
+	content, err := json.Marshal(logs)
+	if err != nil {
+		return err
+	}
+
+	if int64(len(content)) > c.maxSize {
+		return nil
+	}
+
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o600); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	return c.evict()
+}
+
+// evict removes the least recently used entries until the cache fits into
+// maxSize.
+func (c *resultCache) evict() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	var (
+		infos []fs.FileInfo
+		total int64
+	)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		infos = append(infos, info)
+		total += info.Size()
+	}
+
+	slices.SortFunc(infos, func(a, b fs.FileInfo) int {
+		return a.ModTime().Compare(b.ModTime())
+	})
+
+	for _, info := range infos {
+		if total <= c.maxSize {
+			break
+		}
+
+		if err := os.Remove(filepath.Join(c.dir, info.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		total -= info.Size()
+	}
+
+	return nil
+}
+
+func (c *resultCache) clear() error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("error while clearing cache %s: %w", c.dir, err)
+	}
+
+	return nil
+}
+
+// accountKey identifies the account of apiUrl and token in the names of
+// cache files, so that the cached names of accounts and profiles do not mix.
+func accountKey(apiUrl, token string) string {
+	tokenHash := sha256.Sum256([]byte(token))
+	key := strings.TrimSuffix(apiUrl, "/") + "\n" + hex.EncodeToString(tokenHash[:])
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// defaultAccount returns the API URL and token of the default config file,
+// for completing cached names.
+func defaultAccount() (string, string) {
+	opts := &Options{ApiUrl: defaultApiUrl, configFile: defaultConfigFile}
+	if _, err := opts.loadConfig(); err != nil {
+		return "", ""
+	}
+
+	return opts.ApiUrl, opts.Token
+}
+
+// readCacheFile decodes the JSON file name of the cache directory, which
+// holds lookup data such as entity names rather than search results.
+func readCacheFile(name string, v any) error {
+	dir, err := defaultCacheDir()
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, v)
+}
+
+// writeCacheFile stores v as the JSON file name of the cache directory. The
+// name has no .json extension, so the file is not evicted with results.
+func writeCacheFile(name string, v any) error {
+	dir, err := defaultCacheDir()
+	if err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+type cacheCommand struct {
+	fs   *flag.FlagSet
+	help *commandHelp
+}
+
+func NewCacheCommand() *cacheCommand {
+	help := newCommandHelp(cacheClearCommandName, "remove the cached search results and names", "")
+	cmd := &cacheCommand{
+		fs:   help.fs,
+		help: help,
+	}
+
+	help.example("swo-cli cache clear")
+
+	return cmd
+}
+
+func (c *cacheCommand) Init(args []string) error {
+	err := c.help.parse(args)
+	if err != nil {
+		return err
+	}
+
+	if c.fs.NArg() != 0 {
+		return errCacheArgs
+	}
+
+	return nil
+}
+
+func (c *cacheCommand) Run(ctx context.Context) error {
+	cache, err := newResultCache(0)
+	if err != nil {
+		return err
+	}
+
+	if err := cache.clear(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Cleared %s\n", cache.dir)
+	return nil
+}
+
+func (c *cacheCommand) Name() string {
+	return cacheClearCommandName
+}
+
+func (c *cacheCommand) Usage() {
+	c.fs.Usage()
+}
+
+func (c *cacheCommand) Help() *commandHelp {
+	return c.help
+}
@@ -0,0 +1,134 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain keeps the tests from reading or writing the user's cache.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "swo-cli-cache")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	os.Setenv("XDG_CACHE_HOME", dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+
+	os.Exit(code)
+}
+
+func TestCacheable(t *testing.T) {
+	require.False(t, cacheable(Query{}))
+	require.False(t, cacheable(Query{EndTime: now.Add(time.Minute)}))
+	require.False(t, cacheable(Query{EndTime: now.Add(-time.Minute)}))
+	require.False(t, cacheable(Query{EndTime: now.Add(-cacheDelay)}))
+	require.True(t, cacheable(Query{EndTime: now.Add(-cacheDelay - time.Second)}))
+	require.True(t, cacheable(Query{EndTime: now.Add(-time.Hour), Cursor: "/v1/logs?skipToken=next"}))
+}
+
+func TestCachePath(t *testing.T) {
+	cache := &resultCache{dir: t.TempDir(), maxSize: defaultCacheMaxSize}
+	end := now.Add(-time.Hour)
+
+	path, err := cache.path("https://api.example.com/", "1234567", Query{Filter: " host:www42  error ", EndTime: end, PageSize: 100})
+	require.NoError(t, err)
+
+	same, err := cache.path("https://api.example.com", "1234567", Query{Filter: "host:www42 error", EndTime: end.In(time.FixedZone("CET", 3600)), PageSize: 100})
+	require.NoError(t, err)
+	require.Equal(t, path, same)
+
+	for _, q := range []Query{
+		{Filter: "host:www42 error", EndTime: end, PageSize: 50},
+		{Filter: "host:www42 error", EndTime: end, PageSize: 100, Group: "groupValue"},
+		{Filter: "host:www42 error", EndTime: end, PageSize: 100, StartTime: end.Add(-time.Hour)},
+		{Filter: "host:www42 error", EndTime: end, PageSize: 100, Cursor: "/v1/logs?skipToken=next"},
+	} {
+		other, err := cache.path("https://api.example.com", "1234567", q)
+		require.NoError(t, err)
+		require.NotEqual(t, path, other)
+	}
+
+	other, err := cache.path("https://api.example.com", "7654321", Query{Filter: "host:www42 error", EndTime: end, PageSize: 100})
+	require.NoError(t, err)
+	require.NotEqual(t, path, other)
+}
+
+func TestCacheEviction(t *testing.T) {
+	cache := &resultCache{dir: t.TempDir(), maxSize: 200}
+	page := &LogsData{Logs: []Log{{Message: "messageOne"}}}
+
+	var paths []string
+	for i := 0; i < 3; i++ {
+		path := filepath.Join(cache.dir, fmt.Sprintf("%d.json", i))
+		require.NoError(t, cache.put(path, page))
+		used := now.Add(-time.Duration(10-i) * time.Hour)
+		require.NoError(t, os.Chtimes(path, used, used))
+		paths = append(paths, path)
+	}
+
+	_, ok := cache.get(paths[0])
+	require.False(t, ok)
+
+	cached, ok := cache.get(paths[2])
+	require.True(t, ok)
+	require.Equal(t, page.Logs[0].Message, cached.Logs[0].Message)
+}
+
+func TestFetchPageCache(t *testing.T) {
+	server, requests := newPagedServer(t, 1, 2)
+	createConfigFile(t, configFile, fmt.Sprintf("token: 1234567\napi-url: %s", server.URL))
+
+	flags := []string{"--configfile", configFile, "--min-time", "2000-01-01T10:00:00Z", "--max-time", "2000-01-01T11:00:00Z"}
+	for i := 0; i < 2; i++ {
+		cmd := NewLogsCommand()
+		require.NoError(t, cmd.Init(flags))
+
+		q, err := cmd.search.query()
+		require.NoError(t, err)
+
+		logs, err := cmd.search.fetchPage(context.Background(), q)
+		require.NoError(t, err)
+		require.Len(t, logs.Logs, 2)
+		require.Equal(t, 1, *requests)
+	}
+
+	cmd := NewLogsCommand()
+	require.NoError(t, cmd.Init(append(flags, "--no-cache")))
+
+	q, err := cmd.search.query()
+	require.NoError(t, err)
+
+	_, err = cmd.search.fetchPage(context.Background(), q)
+	require.NoError(t, err)
+	require.Equal(t, 2, *requests)
+
+	cacheCmd := NewCacheCommand()
+	require.NoError(t, cacheCmd.Init([]string{}))
+	require.NoError(t, cacheCmd.Run(context.Background()))
+
+	cmd = NewLogsCommand()
+	require.NoError(t, cmd.Init(flags))
+
+	_, err = cmd.search.fetchPage(context.Background(), q)
+	require.NoError(t, err)
+	require.Equal(t, 3, *requests)
+}
+
+func TestCacheCommandInit(t *testing.T) {
+	require.NoError(t, NewCacheCommand().Init([]string{}))
+
+	for _, args := range [][]string{{"purge"}, {"clear", "now"}} {
+		err := NewCacheCommand().Init(args)
+		require.True(t, errors.Is(err, errCacheArgs), "error: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package logs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+var errNothingToResume = errors.New("no checkpoint to resume from")
+
+// checkpoint records how far a paginated fetch got. It is saved after every
+// fully written page, so a run with --resume continues with the next page
+// and never writes a log twice.
+type checkpoint struct {
+	// Query identifies the export the checkpoint belongs to, see
+	// exportCommand.queryHash.
+	Query    string    `json:"query"`
+	Cursor   string    `json:"cursor"`
+	LastTime time.Time `json:"lastTime"`
+	Count    int       `json:"count"`
+
+	// File is the output file that was being written when the checkpoint
+	// was saved and Offset its size on disk at that point. Anything written
+	// after the checkpoint is truncated away when resuming. Size is the
+	// uncompressed size of File that --split-size counts.
+	File   string `json:"file,omitempty"`
+	Offset int64  `json:"offset,omitempty"`
+	Size   int64  `json:"size,omitempty"`
+	Part   int    `json:"part,omitempty"`
+}
+
+func loadCheckpoint(path string) (checkpoint, error) {
+	var cp checkpoint
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, fmt.Errorf("%w: %s does not exist", errNothingToResume, path)
+	}
+	if err != nil {
+		return cp, err
+	}
+
+	if err := json.Unmarshal(content, &cp); err != nil {
+		return cp, fmt.Errorf("error while unmarshaling checkpoint %s: %w", path, err)
+	}
+
+	return cp, nil
+}
+
+// save replaces the checkpoint file atomically so that an interruption
+// never leaves it half written.
+func (cp checkpoint) save(path string) error {
+	content, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o644); err != nil {
+		return fmt.Errorf("error while saving checkpoint: %w", err)
+	}
+
+	return os.Rename(tmp, path)
+}
+
+func removeCheckpoint(path string) error {
+	err := os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
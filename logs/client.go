@@ -3,37 +3,30 @@ package logs
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
-	"math/rand/v2"
 	"net/http"
 	"net/url"
-	"os"
+	"runtime"
 	"strconv"
-	"strings"
 	"time"
 
-	"github.com/fatih/color"
 	"github.com/jskiba/papertrail-cli-poc/version"
 )
 
-type ColorStrFunc func(format string, a ...interface{}) string
-
-var colors = []ColorStrFunc{
-	color.CyanString,
-	color.YellowString,
-	color.GreenString,
-	color.MagentaString,
-	color.RedString,
-}
-
+// Client queries the SWO logs API. It holds no CLI state and can be used
+// directly from Go programs.
 type Client struct {
-	opts       *Options
-	httpClient http.Client
-	output     *os.File
+	apiUrl     string
+	token      string
+	userAgent  string
+	httpClient *http.Client
 }
 
+type ClientOption func(*Client)
+
 type Log struct {
 	Time     time.Time `json:"time"`
 	Message  string    `json:"message"`
@@ -52,120 +45,159 @@ type LogsData struct {
 	PageInfo `json:"pageInfo"`
 }
 
-func NewClient(opts *Options) (*Client, error) {
-	return &Client{
-		httpClient: *http.DefaultClient,
-		opts:       opts,
-		output:     os.Stdout,
-	}, nil
+// Query describes a single page of a log search. Zero values are left out
+// of the request so the server defaults apply.
+type Query struct {
+	Filter    string
+	Group     string
+	StartTime time.Time
+	EndTime   time.Time
+	PageSize  int
+
+	// Cursor continues a previous search from its LogsData.NextPage. The
+	// other fields are ignored when it is set.
+	Cursor string
 }
 
-func (c *Client) prepareRequest(ctx context.Context) (*http.Request, error) {
-	logsEndpoint, err := url.JoinPath(c.opts.ApiUrl, "v1/logs")
-	if err != nil {
-		return nil, err
+func WithToken(token string) ClientOption {
+	return func(c *Client) {
+		c.token = token
 	}
+}
 
-	logsUrl, err := url.Parse(logsEndpoint)
+func WithApiUrl(apiUrl string) ClientOption {
+	return func(c *Client) {
+		c.apiUrl = apiUrl
+	}
+}
+
+func WithHttpClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// DefaultUserAgent identifies the CLI version and platform, for example
+// "swo-cli/0.0.1 (linux/amd64)".
+func DefaultUserAgent() string {
+	return fmt.Sprintf("swo-cli/%s (%s/%s)", version.Version, runtime.GOOS, runtime.GOARCH)
+}
+
+func NewClient(opts ...ClientOption) (*Client, error) {
+	c := &Client{
+		apiUrl:     defaultApiUrl,
+		userAgent:  DefaultUserAgent(),
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if _, err := url.Parse(c.apiUrl); err != nil {
+		return nil, fmt.Errorf("invalid API URL %q: %w", c.apiUrl, err)
+	}
+
+	return c, nil
+}
+
+// Search fetches one page of logs, newest first. The next page is
+// requested by passing LogsData.NextPage as Query.Cursor.
+func (c *Client) Search(ctx context.Context, q Query) (*LogsData, error) {
+	request, err := c.prepareRequest(ctx, q)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error while preparing http request to SWO: %w", err)
+	}
+
+	return c.fetch(request)
+}
+
+func (c *Client) prepareRequest(ctx context.Context, q Query) (*http.Request, error) {
+	if q.Cursor != "" {
+		return c.prepareNextPageRequest(ctx, q.Cursor)
 	}
 
 	params := url.Values{}
-	params.Add("pageSize", strconv.Itoa(c.opts.count))
-	if c.opts.group != "" {
-		params.Add("group", c.opts.group)
+	if q.PageSize > 0 {
+		params.Add("pageSize", strconv.Itoa(q.PageSize))
 	}
-	if c.opts.minTime != "" {
-		params.Add("startTime", c.opts.minTime)
+	if q.Group != "" {
+		params.Add("group", q.Group)
 	}
-	if c.opts.maxTime != "" {
-		params.Add("endTime", c.opts.maxTime)
+	if !q.StartTime.IsZero() {
+		params.Add("startTime", q.StartTime.Format(time.RFC3339))
 	}
-
-	var filter string
-	if c.opts.system != "" {
-		filter = fmt.Sprintf("host:%s", c.opts.system)
+	if !q.EndTime.IsZero() {
+		params.Add("endTime", q.EndTime.Format(time.RFC3339))
 	}
-	if len(c.opts.args) != 0 {
-		if len(filter) == 0 {
-			filter = strings.Join(c.opts.args, " ")
-		} else {
-			filter = filter + " " + strings.Join(c.opts.args, " ")
-		}
+	if q.Filter != "" {
+		params.Add("filter", q.Filter)
 	}
 
-	if filter != "" {
-		params.Add("filter", filter)
+	logsEndpoint, err := url.JoinPath(c.apiUrl, "v1/logs")
+	if err != nil {
+		return nil, err
 	}
 
-	logsUrl.RawQuery = params.Encode()
-	request, err := http.NewRequestWithContext(ctx, "GET", logsUrl.String(), nil)
+	logsUrl, err := url.Parse(logsEndpoint)
 	if err != nil {
 		return nil, err
 	}
 
-	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.opts.Token))
-	request.Header.Add("Accept", "application/json")
+	logsUrl.RawQuery = params.Encode()
 
-	return request, nil
+	return c.newRequest(ctx, logsUrl.String())
 }
 
-func (c *Client) printResult(logs *LogsData) error {
-	if c.opts.json {
-		jsonFormat, err := json.Marshal(logs)
-		if err != nil {
-			return err
-		}
+// prepareNextPageRequest builds a request for the page referenced by
+// PageInfo.NextPage, which SWO returns relative to the API URL.
+func (c *Client) prepareNextPageRequest(ctx context.Context, nextPage string) (*http.Request, error) {
+	baseUrl, err := url.Parse(c.apiUrl)
+	if err != nil {
+		return nil, err
+	}
 
-		_, err = fmt.Fprintln(c.output, string(jsonFormat))
-		return err
-	}
-
-	hostnameColorIdx := -1
-	programColorIdx := -1
-	switch c.opts.color {
-	case system:
-		hostnameColorIdx = rand.IntN(len(colors))
-	case program:
-		programColorIdx = rand.IntN(len(colors))
-	case all:
-		programColorIdx = rand.IntN(len(colors))
-		hostnameColorIdx = rand.IntN(len(colors))
-		for hostnameColorIdx == programColorIdx {
-			hostnameColorIdx = rand.IntN(len(colors))
-		}
-	default:
+	pageUrl, err := url.Parse(nextPage)
+	if err != nil {
+		return nil, err
 	}
 
-	for i := len(logs.Logs) - 1; i >= 0; i-- {
-		l := logs.Logs[i]
-		hostname := l.Hostname
-		program := l.Program
-		if hostnameColorIdx != -1 {
-			hostname = colors[hostnameColorIdx](hostname)
-		}
-		if programColorIdx != -1 {
-			program = colors[programColorIdx](program)
-		}
+	return c.newRequest(ctx, baseUrl.ResolveReference(pageUrl).String())
+}
 
-		fmt.Fprintf(c.output, "%s %s %s %s\n", l.Time.Format("Jan 02 15:04:05"), hostname, program, l.Message)
+func (c *Client) newRequest(ctx context.Context, requestUrl string) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", requestUrl, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	request.Header.Add("Accept", "application/json")
+	request.Header.Add("Accept-Encoding", acceptEncoding)
+	if c.userAgent != "" {
+		request.Header.Set("User-Agent", c.userAgent)
+	}
+
+	return request, nil
 }
 
-func (c *Client) Run(ctx context.Context) error {
-	if c.opts.version {
-		fmt.Fprintln(c.output, version.Version)
-		return nil
+func (c *Client) fetch(request *http.Request) (*LogsData, error) {
+	var logs LogsData
+	if err := c.get(request, &logs); err != nil {
+		return nil, err
 	}
 
-	request, err := c.prepareRequest(ctx)
-	if err != nil {
-		return fmt.Errorf("error while preparing http request to SWO: %w", err)
-	}
+	return &logs, nil
+}
 
+// get sends request and decodes the JSON response into v.
+func (c *Client) get(request *http.Request, v any) error {
 	response, err := c.httpClient.Do(request)
 	if err != nil {
 		return fmt.Errorf("error while sending http request to SWO: %w", err)
@@ -177,24 +209,40 @@ func (c *Client) Run(ctx context.Context) error {
 		}
 	}()
 
-	content, err := io.ReadAll(response.Body)
+	encoding := response.Header.Get("Content-Encoding")
+	compressed := &countingReader{r: response.Body}
+	body, err := decompress(compressed, encoding)
 	if err != nil {
-		return fmt.Errorf("error while reading http response body from SWO: %w", err)
+		return fmt.Errorf("error while decompressing http response body from SWO: %w", err)
 	}
+	defer body.Close()
 
 	if !(response.StatusCode >= 200 && response.StatusCode < 300) {
+		content, err := io.ReadAll(body)
+		if err != nil {
+			return fmt.Errorf("error while reading http response body from SWO: %w", err)
+		}
+
 		return fmt.Errorf("received %d status code, response body: %s", response.StatusCode, string(content))
 	}
 
-	if len(content) == 0 {
-		return nil
-	}
+	decompressed := &countingReader{r: body}
 
-	var logs LogsData
-	err = json.Unmarshal(content, &logs)
-	if err != nil {
+	err = json.NewDecoder(decompressed).Decode(v)
+	if err != nil && !errors.Is(err, io.EOF) {
 		return fmt.Errorf("error while unmarshaling http response body from SWO: %w", err)
 	}
 
-	return c.printResult(&logs)
+	// read the rest so that the byte counts are complete and the connection can be reused
+	if _, err := io.Copy(io.Discard, decompressed); err != nil {
+		return fmt.Errorf("error while reading http response body from SWO: %w", err)
+	}
+
+	slog.Debug("Received SWO response",
+		slog.String("encoding", encoding),
+		slog.Int64("compressedBytes", compressed.n),
+		slog.Int64("decompressedBytes", decompressed.n),
+	)
+
+	return nil
 }
@@ -1,15 +1,17 @@
 package logs
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
@@ -124,7 +126,10 @@ func TestPrepareRequest(t *testing.T) {
 			err := cmd.Init(tc.flags)
 			require.NoError(t, err)
 
-			request, err := cmd.client.prepareRequest(context.Background())
+			q, err := cmd.search.query()
+			require.NoError(t, err)
+
+			request, err := cmd.search.client.prepareRequest(context.Background(), q)
 			require.NoError(t, err)
 
 			values := request.URL.Query()
@@ -136,6 +141,7 @@ func TestPrepareRequest(t *testing.T) {
 			for k, v := range map[string][]string{
 				"Authorization": {fmt.Sprintf("Bearer %s", token)},
 				"Accept":        {"application/json"},
+				"User-Agent":    {fmt.Sprintf("swo-cli/%s (%s/%s)", version.Version, runtime.GOOS, runtime.GOARCH)},
 			} {
 				require.ElementsMatch(t, v, header[k])
 			}
@@ -144,6 +150,18 @@ func TestPrepareRequest(t *testing.T) {
 
 }
 
+func TestUserAgentSuffix(t *testing.T) {
+	createConfigFile(t, configFile, "token: 1234567\nuser-agent-suffix: nightly-export")
+
+	cmd := NewLogsCommand()
+	err := cmd.Init([]string{"--configfile", configFile})
+	require.NoError(t, err)
+
+	request, err := cmd.search.client.prepareRequest(context.Background(), Query{})
+	require.NoError(t, err)
+	require.Equal(t, DefaultUserAgent()+" nightly-export", request.Header.Get("User-Agent"))
+}
+
 func TestRun(t *testing.T) {
 	location, err := time.LoadLocation("GMT")
 	require.NoError(t, err)
@@ -182,41 +200,22 @@ api-url: %s
 `, token, fmt.Sprintf("http://%s", listener.Addr().String()))
 	createConfigFile(t, configFile, yamlStr)
 
-	r, w, err := os.Pipe()
-	require.NoError(t, err)
-
 	cmd := NewLogsCommand()
 	err = cmd.Init([]string{"--configfile", configFile, "--json"})
 	require.NoError(t, err)
 
-	cmd.client.output = w
+	output := &bytes.Buffer{}
+	cmd.search.output = output
 
-	outputComapreDone := make(chan struct{})
-
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		defer close(outputComapreDone)
-
-		output, err := io.ReadAll(r)
-		require.NoError(t, err)
-
-		data, err := json.Marshal(logsData)
-		require.NoError(t, err)
-		require.Equal(t, string(data), string(output[:len(output)-1])) // last char is a new line character
-	}()
-
-	go func() {
-		<-outputComapreDone
-		_ = server.Shutdown(context.Background())
-	}()
-
-	err = cmd.client.Run(context.Background())
+	err = cmd.search.Run(context.Background())
 	require.NoError(t, err)
 
-	w.Close()
-
+	_ = server.Shutdown(context.Background())
 	wg.Wait()
+
+	data, err := json.Marshal(logsData)
+	require.NoError(t, err)
+	require.Equal(t, string(data)+"\n", output.String())
 }
 
 func TestPrintResultStandard(t *testing.T) {
@@ -230,31 +229,16 @@ func TestPrintResultStandard(t *testing.T) {
 	err = cmd.Init([]string{"--configfile", configFile})
 	require.NoError(t, err)
 
-	r, w, err := os.Pipe()
-	require.NoError(t, err)
-	cmd.client.output = w
-
-	wg := sync.WaitGroup{}
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
+	output := &bytes.Buffer{}
+	cmd.search.output = output
 
-		output, err := io.ReadAll(r)
-		require.NoError(t, err)
+	err = cmd.search.printResult(&logsData)
+	require.NoError(t, err)
 
-		expectStr := fmt.Sprintf(`%s hostnameTwo programTwo messageTwo
+	expectStr := fmt.Sprintf(`%s hostnameTwo programTwo messageTwo
 %s hostnameOne programOne messageOne
 `, logsData.Logs[1].Time.Format("Jan 02 15:04:05"), logsData.Logs[0].Time.Format("Jan 02 15:04:05")) // SWO returns fresh logs as first in the logs list
-		require.Equal(t, expectStr, string(output))
-	}()
-
-	err = cmd.client.printResult(&logsData)
-	require.NoError(t, err)
-
-	err = w.Close()
-	require.NoError(t, err)
-
-	wg.Wait()
+	require.Equal(t, expectStr, output.String())
 }
 
 func TestPrintResultJSON(t *testing.T) {
@@ -268,55 +252,40 @@ func TestPrintResultJSON(t *testing.T) {
 	err = cmd.Init([]string{"--configfile", configFile, "--json"})
 	require.NoError(t, err)
 
-	r, w, err := os.Pipe()
-	require.NoError(t, err)
-	cmd.client.output = w
+	output := &bytes.Buffer{}
+	cmd.search.output = output
 
-	wg := sync.WaitGroup{}
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-
-		output, err := io.ReadAll(r)
-		require.NoError(t, err)
+	err = cmd.search.printResult(&logsData)
+	require.NoError(t, err)
 
-		expectedStr := `
-		{
-			"logs":[
-				{
-					"time":"%s",
-					"message":"messageOne",
-					"hostname":"hostnameOne",
-					"severity":"severityOne",
-					"program":"programOne"
-				},
-				{
-					"time":"%s",
-					"message":"messageTwo",
-					"hostname":"hostnameTwo",
-					"severity":"severityTwo",
-					"program":"programTwo"
-				}
-			],
-			"pageInfo":{
-				"prevPage":"prevPageValue",
-				"nextPage":""
+	expectedStr := `
+	{
+		"logs":[
+			{
+				"time":"%s",
+				"message":"messageOne",
+				"hostname":"hostnameOne",
+				"severity":"severityOne",
+				"program":"programOne"
+			},
+			{
+				"time":"%s",
+				"message":"messageTwo",
+				"hostname":"hostnameTwo",
+				"severity":"severityTwo",
+				"program":"programTwo"
 			}
+		],
+		"pageInfo":{
+			"prevPage":"prevPageValue",
+			"nextPage":""
 		}
-		`
-		trimmed := strings.TrimSpace(fmt.Sprintf(expectedStr, logsData.Logs[0].Time.Format(time.RFC3339Nano), logsData.Logs[1].Time.Format(time.RFC3339Nano)))
-		trimmed = strings.ReplaceAll(trimmed, "\t", "")
-		trimmed = strings.ReplaceAll(trimmed, "\n", "")
-		require.Equal(t, trimmed, string(output[:len(output)-1])) // last char is a new line character
-	}()
-
-	err = cmd.client.printResult(&logsData)
-	require.NoError(t, err)
-
-	err = w.Close()
-	require.NoError(t, err)
-
-	wg.Wait()
+	}
+	`
+	trimmed := strings.TrimSpace(fmt.Sprintf(expectedStr, logsData.Logs[0].Time.Format(time.RFC3339Nano), logsData.Logs[1].Time.Format(time.RFC3339Nano)))
+	trimmed = strings.ReplaceAll(trimmed, "\t", "")
+	trimmed = strings.ReplaceAll(trimmed, "\n", "")
+	require.Equal(t, trimmed+"\n", output.String())
 }
 
 func TestRunVersion(t *testing.T) {
@@ -326,24 +295,71 @@ func TestRunVersion(t *testing.T) {
 	err := cmd.Init([]string{"--configfile", configFile, "--version"})
 	require.NoError(t, err)
 
-	r, w, err := os.Pipe()
+	output := &bytes.Buffer{}
+	cmd.search.output = output
+
+	err = cmd.search.Run(context.Background())
 	require.NoError(t, err)
-	cmd.client.output = w
+	require.Equal(t, version.Version+"\n", output.String())
+}
 
-	wg := sync.WaitGroup{}
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
+func TestSearch(t *testing.T) {
+	startTime, err := time.Parse(time.RFC3339, "2000-01-01T10:00:00Z")
+	require.NoError(t, err)
+
+	var requests []*http.Request
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/logs", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+
+		page := logsData
+		page.PageInfo = PageInfo{}
+		if r.URL.Query().Get("skipToken") == "" {
+			page.PageInfo.NextPage = "/v1/logs?skipToken=next"
+		}
 
-		output, err := io.ReadAll(r)
+		data, err := json.Marshal(page)
 		require.NoError(t, err)
-		require.Equal(t, version.Version+"\n", string(output))
-	}()
 
-	err = cmd.client.Run(context.Background())
+		_, err = w.Write(data)
+		require.NoError(t, err)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(
+		WithToken("1234567"),
+		WithApiUrl(server.URL),
+		WithHttpClient(server.Client()),
+		WithUserAgent("my-service/1.0"),
+	)
 	require.NoError(t, err)
 
-	w.Close()
-
-	wg.Wait()
+	q := Query{
+		Filter:    "host:www42 error",
+		Group:     "groupValue",
+		StartTime: startTime,
+		EndTime:   startTime.Add(time.Hour),
+		PageSize:  2,
+	}
+	result, err := client.Search(context.Background(), q)
+	require.NoError(t, err)
+	require.Len(t, result.Logs, 2)
+	require.Equal(t, "/v1/logs?skipToken=next", result.NextPage)
+
+	require.Equal(t, url.Values{
+		"filter":    {"host:www42 error"},
+		"group":     {"groupValue"},
+		"startTime": {"2000-01-01T10:00:00Z"},
+		"endTime":   {"2000-01-01T11:00:00Z"},
+		"pageSize":  {"2"},
+	}, requests[0].URL.Query())
+	require.Equal(t, "Bearer 1234567", requests[0].Header.Get("Authorization"))
+	require.Equal(t, "my-service/1.0", requests[0].Header.Get("User-Agent"))
+
+	q.Cursor = result.NextPage
+	result, err = client.Search(context.Background(), q)
+	require.NoError(t, err)
+	require.Empty(t, result.NextPage)
+	require.Equal(t, url.Values{"skipToken": {"next"}}, requests[1].URL.Query())
 }
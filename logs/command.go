@@ -2,77 +2,103 @@ package logs
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"os"
 )
 
 const logsCommandName = "logs"
 
 type command struct {
 	fs     *flag.FlagSet
-	client *Client
+	help   *commandHelp
+	search *searcher
 	opts   *Options
 }
 
 func NewLogsCommand() *command {
+	help := newCommandHelp(logsCommandName, "command-line search for SolarWinds Observability log management service", "[--] [query]")
 	cmd := &command{
-		fs: flag.NewFlagSet(logsCommandName, flag.ContinueOnError),
+		fs:   help.fs,
+		help: help,
 		opts: &Options{},
 	}
 
-	cmd.fs.Usage = func() {
-		fmt.Printf("  %36s\n", "logs - command-line search for SolarWinds Observability log management service")
-		fmt.Printf("    %2s, %16s %70s\n", "-h", "--help", "Show usage")
-		fmt.Printf("    %2s  %16s %70s\n", "", "--count NUMBER", "Number of log entries to search (100)")
-		fmt.Printf("    %2s  %16s %70s\n", "", "--min-time MIN", "Earliest time to search from")
-		fmt.Printf("    %2s  %16s %70s\n", "", "--max-time MAX", "Latest time to search from")
-		fmt.Printf("    %2s, %16s %70s\n", "-c", "--configfile", "Path to config (~/.swo-cli.yaml)")
-		fmt.Printf("    %2s, %16s %70s\n", "-g", "--group GROUP_ID", "Group ID to search")
-		fmt.Printf("    %2s, %16s %70s\n", "-s", "--system SYSTEM", "System to search")
-		fmt.Printf("    %2s, %16s %70s\n", "-j", "--json", "Output raw JSON data (off)")
-		fmt.Printf("    %2s  %16s %70s\n", "", "--color [program|system|all|off]", "")
-		fmt.Printf("    %2s, %16s %70s\n", "-V", "--version", "Display the version and exit")
-
-		fmt.Println()
-
-		fmt.Println("    Usage:")
-		fmt.Println("      swo-cli logs [--min-time time] [--max-time time] [-g group-id] [-s system]")
-		fmt.Println("        [-c swo-cli.yml] [-j] [--color attributes] [--] [query]")
-
-		fmt.Println()
-
-		fmt.Println("    Examples:")
-		fmt.Printf("    %s logs something\n", os.Args[0])
-		fmt.Printf("    %s logs 1.2.3 Failure\n", os.Args[0])
-		fmt.Printf(`    %s logs -s ns1 "connection refused"\n`, os.Args[0])
-		fmt.Printf(`    %s logs "(www OR db) (nginx OR pgsql) -accepted"%v`, os.Args[0], "\n")
-		fmt.Printf(`    %s logs -g <SWO_GROUP_ID> --color all "(nginx OR pgsql) -accepted"%v`, os.Args[0], "\n")
-		fmt.Printf(`    %s logs --min-time 'yesterday at noon' --max-time 'today at 4am' -g <SWO_GROUP_ID>%v`, os.Args[0], "\n")
-		fmt.Printf("    %s logs -- -redis\n", os.Args[0])
-	}
-
-	cmd.fs.IntVar(&cmd.opts.count, "count", defaultCount, "")
-	cmd.fs.StringVar(&cmd.opts.configFile, "c", "", "")
-	cmd.fs.StringVar(&cmd.opts.configFile, "configfile", defaultConfigFile, "")
-	cmd.fs.StringVar(&cmd.opts.group, "g", "", "")
-	cmd.fs.StringVar(&cmd.opts.group, "group", "", "")
-	cmd.fs.StringVar(&cmd.opts.system, "s", "", "")
-	cmd.fs.StringVar(&cmd.opts.system, "system", "", "")
-	cmd.fs.StringVar(&cmd.opts.color, "color", "", "")
-	cmd.fs.StringVar(&cmd.opts.ApiUrl, "api-url", defaultApiUrl, "")
-	cmd.fs.StringVar(&cmd.opts.minTime, "min-time", "", "")
-	cmd.fs.StringVar(&cmd.opts.maxTime, "max-time", "", "")
-	cmd.fs.BoolVar(&cmd.opts.json, "j", false, "")
-	cmd.fs.BoolVar(&cmd.opts.json, "json", false, "")
-	cmd.fs.BoolVar(&cmd.opts.version, "V", false, "")
-	cmd.fs.BoolVar(&cmd.opts.version, "version", false, "")
+	help.intVar(&cmd.opts.count, "count", defaultCount, "NUMBER", "Number of log entries to search")
+	help.boolVar(&cmd.opts.json, "j,json", "Output raw JSON data")
+	help.intVar(&cmd.opts.after, "A", 0, "NUMBER", "Print NUMBER logs after each match from the same system and program")
+	help.intVar(&cmd.opts.before, "B", 0, "NUMBER", "Print NUMBER logs before each match from the same system and program")
+	help.intVar(&cmd.opts.contextLines, "C", 0, "NUMBER", "Print NUMBER logs before and after each match")
+	help.durationVar(&cmd.opts.contextWindow, "context-window", 0, "DURATION", "Time window searched for context logs").def = "1m"
+	help.stringVar(&cmd.opts.color, "color", "", "[program|system|all|off]", "Color the program, the system, both or nothing")
+	help.boolVar(&cmd.opts.version, "V,version", "Display the version and exit")
+	registerSearchFlags(help, cmd.opts, "print")
+	registerParallelFlags(help, cmd.opts)
+	registerOutputFlags(help, cmd.opts)
+	registerConnectionFlags(help, cmd.opts)
+
+	help.example(
+		"swo-cli logs something",
+		"swo-cli logs 1.2.3 Failure",
+		`swo-cli logs -s ns1 "connection refused"`,
+		`swo-cli logs "(www OR db) (nginx OR pgsql) -accepted"`,
+		`swo-cli logs -g <SWO_GROUP_ID> --color all "(nginx OR pgsql) -accepted"`,
+		"swo-cli logs --min-time 'yesterday at noon' --max-time 'today at 4am' -g <SWO_GROUP_ID>",
+		"swo-cli logs -- -redis",
+		`swo-cli logs --grep 'timeout after \d+ms' --match program=^nginx --count 20`,
+		"swo-cli logs -C 3 --grep 'panic:' --min-time '1 hour ago'",
+	)
 
 	return cmd
 }
 
+// registerSearchFlags registers the flags shared by every command that
+// searches logs. verb describes what the command does with the logs
+// that pass the local filters.
+func registerSearchFlags(h *commandHelp, opts *Options, verb string) {
+	h.stringVar(&opts.minTime, "min-time", "", "MIN", "Earliest time to search from")
+	h.stringVar(&opts.maxTime, "max-time", "", "MAX", "Latest time to search from")
+	h.stringVar(&opts.configFile, "c,configfile", defaultConfigFile, "PATH", "Path to config")
+	h.stringVar(&opts.group, "g,group", "", "GROUP", "ID or name of the group to search, see swo-cli groups")
+	h.stringVar(&opts.system, "s,system", "", "SYSTEM", "System to search")
+	h.stringVar(&opts.grep, "grep", "", "REGEX", fmt.Sprintf("Only %s logs whose message matches REGEX", verb))
+	h.stringVar(&opts.grepV, "grep-v", "", "REGEX", fmt.Sprintf("Only %s logs whose message does not match REGEX", verb))
+	h.variable(&opts.match, "match", "FIELD=REGEX", fmt.Sprintf("Only %s logs whose field matches REGEX (repeatable)", verb))
+	h.stringVar(&opts.fromFile, "from-file,offline", "", "PATH", "Search exported or --json output files instead of SWO, - for stdin")
+}
+
+// registerConnectionFlags registers the flags of commands that talk to
+// the API.
+func registerConnectionFlags(h *commandHelp, opts *Options) {
+	opts.flags = h.fs
+	h.stringVar(&opts.ApiUrl, "api-url", defaultApiUrl, "URL", "Base URL of the SWO API")
+	h.durationVar(&opts.Timeout, "timeout", 0, "DURATION", "Timeout of a whole HTTP request").def = "1m"
+	h.durationVar(&opts.DialTimeout, "dial-timeout", 0, "DURATION", "Timeout of establishing a connection").def = "10s"
+	h.stringVar(&opts.Proxy, "proxy", "", "URL", "Proxy to use instead of HTTPS_PROXY")
+	h.stringVar(&opts.CAFile, "ca-file", "", "PATH", "Extra PEM CA bundle to trust")
+	h.stringVar(&opts.CertFile, "cert-file", "", "PATH", "PEM client certificate for mutual TLS")
+	h.stringVar(&opts.KeyFile, "key-file", "", "PATH", "PEM client key for mutual TLS")
+	h.boolVar(&opts.Insecure, "insecure", "Skip TLS certificate verification")
+	h.stringVar(&opts.UserAgentSuffix, "user-agent-suffix", "", "TEXT", "Appended to the User-Agent header to identify automation")
+	h.float64Var(&opts.RateLimit, "rate-limit", 0, "NUMBER", "Maximum requests per second").def = "5 with --parallel, otherwise off"
+	h.boolVar(&opts.noCache, "no-cache", "Do not use the cache of results with a past --max-time")
+}
+
+// registerOutputFlags registers the flags of commands that print to stdout.
+func registerOutputFlags(h *commandHelp, opts *Options) {
+	h.stringVar(&opts.outputFile, "output-file", "", "PATH", "Append output to PATH instead of stdout")
+	h.stringVar(&opts.outputMaxSize, "output-max-size", "", "SIZE", "Rotate the output file when it would exceed SIZE, e.g. 100M").def = "off"
+	h.intVar(&opts.outputBackups, "output-backups", 0, "NUMBER", "Number of rotated output files to keep").def = "3"
+}
+
+// registerParallelFlags registers the flags of commands that fetch the
+// newest --count logs and can split the search across sub-ranges.
+func registerParallelFlags(h *commandHelp, opts *Options) {
+	h.intVar(&opts.parallel, "parallel", 0, "NUMBER", "Split --min-time to --max-time into NUMBER ranges fetched concurrently").def = "off"
+}
+
 func (c *command) Init(args []string) error {
-	err := c.fs.Parse(args)
+	err := c.help.parse(args)
 	if err != nil {
 		return err
 	}
@@ -82,22 +108,24 @@ func (c *command) Init(args []string) error {
 		return err
 	}
 
-	client, err := NewClient(opts)
+	search, err := newSearcher(opts)
 	if err != nil {
 		return err
 	}
 
-	c.client = client
+	c.search = search
 
 	return nil
 }
 
 func (c *command) Run(ctx context.Context) error {
-	if c.client == nil {
+	if c.search == nil {
 		return fmt.Errorf("%s command was not initialized", logsCommandName)
 	}
 
-	return c.client.Run(ctx)
+	err := c.search.Run(ctx)
+
+	return errors.Join(err, c.search.Close())
 }
 
 func (c *command) Name() string {
@@ -107,3 +135,7 @@ func (c *command) Name() string {
 func (c *command) Usage() {
 	c.fs.Usage()
 }
+
+func (c *command) Help() *commandHelp {
+	return c.help
+}
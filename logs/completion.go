@@ -0,0 +1,414 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+const (
+	completionCommandName = "completion"
+	completionValuesArg   = "values"
+	cliName               = "swo-cli"
+)
+
+var (
+	errCompletionShell = errors.New("expected shell: bash, zsh or fish")
+	errCompletionValue = errors.New("no completion values for flag")
+
+	completionShells = []string{"bash", "zsh", "fish"}
+
+	// completionValues lists the candidates of flags whose values are only
+	// known at runtime. The generated scripts fetch them by running
+	// `swo-cli completion values FLAG`.
+	completionValues = map[string]func() []string{
+		"color":   func() []string { return []string{program, system, all, off} },
+		"by":      func() []string { return []string{byHostname, byProgram, bySeverity, byTime} },
+		"profile": profileNames,
+		"g":       cachedGroupNames,
+		"group":   cachedGroupNames,
+		"s":       completeEntityNames,
+		"system":  completeEntityNames,
+	}
+
+	// completionArgs lists the positional arguments of commands.
+	completionArgs = map[string][]string{
+		completionCommandName: completionShells,
+	}
+
+	// fileFlags complete file names.
+	fileFlags = []string{"c", "configfile", "output-file", "ca-file", "cert-file", "key-file", "from-file", "offline", "dir"}
+)
+
+type completionCommand struct {
+	fs       *flag.FlagSet
+	help     *commandHelp
+	registry *Registry
+	shell    string
+	flag     string
+}
+
+type completionFlag struct {
+	name  string
+	value bool // the flag takes a value
+}
+
+// completionNode is a word completed after swo-cli: a command, an alias of
+// a command or a group of subcommands.
+type completionNode struct {
+	word     string
+	cmd      Command
+	children []completionNode
+}
+
+func NewCompletionCommand(registry *Registry) *completionCommand {
+	help := newCommandHelp(completionCommandName, "print a shell completion script", "bash|zsh|fish")
+	cmd := &completionCommand{
+		fs:       help.fs,
+		help:     help,
+		registry: registry,
+	}
+
+	help.example(
+		"swo-cli completion bash > /etc/bash_completion.d/swo-cli",
+		`swo-cli completion zsh > "${fpath[1]}/_swo-cli"`,
+		"swo-cli completion fish > ~/.config/fish/completions/swo-cli.fish",
+	)
+
+	return cmd
+}
+
+func (c *completionCommand) Init(args []string) error {
+	err := c.help.parse(args)
+	if err != nil {
+		return err
+	}
+
+	if c.fs.NArg() == 2 && c.fs.Arg(0) == completionValuesArg {
+		if _, ok := completionValues[c.fs.Arg(1)]; !ok {
+			return fmt.Errorf("%w %s", errCompletionValue, c.fs.Arg(1))
+		}
+
+		c.flag = c.fs.Arg(1)
+		return nil
+	}
+
+	if c.fs.NArg() != 1 || !slices.Contains(completionShells, c.fs.Arg(0)) {
+		return errCompletionShell
+	}
+
+	c.shell = c.fs.Arg(0)
+
+	return nil
+}
+
+func (c *completionCommand) Run(ctx context.Context) error {
+	if c.flag != "" {
+		_, err := fmt.Fprintln(os.Stdout, strings.Join(completionValues[c.flag](), "\n"))
+		return err
+	}
+
+	return c.writeScript(os.Stdout)
+}
+
+func (c *completionCommand) writeScript(w io.Writer) error {
+	switch c.shell {
+	case "bash":
+		return c.writeBash(w)
+	case "zsh":
+		return c.writeZsh(w)
+	case "fish":
+		return c.writeFish(w)
+	default:
+		return errCompletionShell
+	}
+}
+
+// nodes returns the commands and aliases, with two word commands grouped
+// under their first word.
+func (c *completionCommand) nodes() []completionNode {
+	var nodes []completionNode
+	add := func(word string, cmd Command) {
+		group, sub, nested := strings.Cut(word, " ")
+		if !nested {
+			nodes = append(nodes, completionNode{word: word, cmd: cmd})
+			return
+		}
+
+		i := slices.IndexFunc(nodes, func(n completionNode) bool { return n.word == group })
+		if i < 0 {
+			nodes = append(nodes, completionNode{word: group})
+			i = len(nodes) - 1
+		}
+		nodes[i].children = append(nodes[i].children, completionNode{word: sub, cmd: cmd})
+	}
+
+	for _, cmd := range c.registry.commands {
+		add(cmd.Name(), cmd)
+		for _, alias := range c.registry.aliasesOf(cmd.Name()) {
+			add(alias, cmd)
+		}
+	}
+
+	return nodes
+}
+
+func nodeWords(nodes []completionNode) string {
+	var words []string
+	for _, node := range nodes {
+		words = append(words, node.word)
+	}
+
+	return strings.Join(words, " ")
+}
+
+func completionFlags(fs *flag.FlagSet) []completionFlag {
+	var flags []completionFlag
+	fs.VisitAll(func(f *flag.Flag) {
+		flags = append(flags, completionFlag{name: f.Name, value: !isBoolFlag(f)})
+	})
+
+	return flags
+}
+
+// option returns the flag as it is usually spelled, -x or --name.
+func (f completionFlag) option() string {
+	if len(f.name) == 1 {
+		return "-" + f.name
+	}
+
+	return "--" + f.name
+}
+
+// args returns the positional arguments of cmd; help takes a command.
+func (c *completionCommand) args(cmd Command) []string {
+	if cmd.Name() == helpCommandName {
+		return strings.Fields(nodeWords(c.nodes()))
+	}
+
+	return completionArgs[cmd.Name()]
+}
+
+func (c *completionCommand) words(cmd Command) string {
+	words := slices.Clone(c.args(cmd))
+	for _, f := range completionFlags(cmd.Help().fs) {
+		words = append(words, f.option())
+	}
+
+	return strings.Join(words, " ")
+}
+
+// valueFlags returns the options completed by completionValues and by file
+// names, and the other options that take a value.
+func (c *completionCommand) valueFlags() (map[string][]string, []string, []string) {
+	values := map[string][]string{}
+	var files, others []string
+	for _, cmd := range c.registry.commands {
+		for _, f := range completionFlags(cmd.Help().fs) {
+			option := f.option()
+			switch {
+			case !f.value:
+			case completionValues[f.name] != nil:
+				if !slices.Contains(values[f.name], option) {
+					values[f.name] = append(values[f.name], option)
+				}
+			case slices.Contains(fileFlags, f.name):
+				if !slices.Contains(files, option) {
+					files = append(files, option)
+				}
+			default:
+				if !slices.Contains(others, option) {
+					others = append(others, option)
+				}
+			}
+		}
+	}
+
+	return values, files, others
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+func (c *completionCommand) writeBash(w io.Writer) error {
+	values, files, others := c.valueFlags()
+	nodes := c.nodes()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %s\n", cliName)
+	fmt.Fprintf(&b, "_swo_cli() {\n")
+	fmt.Fprintf(&b, "    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	fmt.Fprintf(&b, "    if [[ $COMP_CWORD -eq 1 ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", nodeWords(nodes))
+	fmt.Fprintf(&b, "        return\n")
+	fmt.Fprintf(&b, "    fi\n")
+	fmt.Fprintf(&b, "    case \"$prev\" in\n")
+	for _, name := range sortedKeys(values) {
+		fmt.Fprintf(&b, "        %s)\n", strings.Join(values[name], "|"))
+		fmt.Fprintf(&b, "            COMPREPLY=($(compgen -W \"$(%s completion values %s 2>/dev/null)\" -- \"$cur\"))\n", cliName, name)
+		fmt.Fprintf(&b, "            return ;;\n")
+	}
+	if len(files) > 0 {
+		fmt.Fprintf(&b, "        %s)\n", strings.Join(files, "|"))
+		fmt.Fprintf(&b, "            COMPREPLY=($(compgen -f -- \"$cur\"))\n")
+		fmt.Fprintf(&b, "            return ;;\n")
+	}
+	if len(others) > 0 {
+		fmt.Fprintf(&b, "        %s)\n", strings.Join(others, "|"))
+		fmt.Fprintf(&b, "            return ;;\n")
+	}
+	fmt.Fprintf(&b, "    esac\n")
+	fmt.Fprintf(&b, "    case \"${COMP_WORDS[1]}\" in\n")
+	for _, node := range nodes {
+		fmt.Fprintf(&b, "        %s)\n", node.word)
+		if node.cmd != nil {
+			fmt.Fprintf(&b, "            COMPREPLY=($(compgen -W \"%s\" -- \"$cur\")) ;;\n", c.words(node.cmd))
+			continue
+		}
+
+		fmt.Fprintf(&b, "            if [[ $COMP_CWORD -eq 2 ]]; then\n")
+		fmt.Fprintf(&b, "                COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", nodeWords(node.children))
+		fmt.Fprintf(&b, "                return\n")
+		fmt.Fprintf(&b, "            fi\n")
+		fmt.Fprintf(&b, "            case \"${COMP_WORDS[2]}\" in\n")
+		for _, child := range node.children {
+			fmt.Fprintf(&b, "                %s)\n", child.word)
+			fmt.Fprintf(&b, "                    COMPREPLY=($(compgen -W \"%s\" -- \"$cur\")) ;;\n", c.words(child.cmd))
+		}
+		fmt.Fprintf(&b, "            esac ;;\n")
+	}
+	fmt.Fprintf(&b, "    esac\n")
+	fmt.Fprintf(&b, "}\n")
+	fmt.Fprintf(&b, "complete -F _swo_cli %s\n", cliName)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (c *completionCommand) writeZsh(w io.Writer) error {
+	values, files, others := c.valueFlags()
+	nodes := c.nodes()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %s\n", cliName)
+	fmt.Fprintf(&b, "_swo_cli() {\n")
+	fmt.Fprintf(&b, "    if (( CURRENT == 2 )); then\n")
+	fmt.Fprintf(&b, "        compadd -- %s\n", nodeWords(nodes))
+	fmt.Fprintf(&b, "        return\n")
+	fmt.Fprintf(&b, "    fi\n")
+	fmt.Fprintf(&b, "    case ${words[CURRENT-1]} in\n")
+	for _, name := range sortedKeys(values) {
+		fmt.Fprintf(&b, "        %s)\n", strings.Join(values[name], "|"))
+		fmt.Fprintf(&b, "            compadd -- ${(f)\"$(%s completion values %s 2>/dev/null)\"}\n", cliName, name)
+		fmt.Fprintf(&b, "            return ;;\n")
+	}
+	if len(files) > 0 {
+		fmt.Fprintf(&b, "        %s)\n", strings.Join(files, "|"))
+		fmt.Fprintf(&b, "            _files\n")
+		fmt.Fprintf(&b, "            return ;;\n")
+	}
+	if len(others) > 0 {
+		fmt.Fprintf(&b, "        %s)\n", strings.Join(others, "|"))
+		fmt.Fprintf(&b, "            return ;;\n")
+	}
+	fmt.Fprintf(&b, "    esac\n")
+	fmt.Fprintf(&b, "    case ${words[2]} in\n")
+	for _, node := range nodes {
+		fmt.Fprintf(&b, "        %s)\n", node.word)
+		if node.cmd != nil {
+			fmt.Fprintf(&b, "            compadd -- %s ;;\n", c.words(node.cmd))
+			continue
+		}
+
+		fmt.Fprintf(&b, "            if (( CURRENT == 3 )); then\n")
+		fmt.Fprintf(&b, "                compadd -- %s\n", nodeWords(node.children))
+		fmt.Fprintf(&b, "                return\n")
+		fmt.Fprintf(&b, "            fi\n")
+		fmt.Fprintf(&b, "            case ${words[3]} in\n")
+		for _, child := range node.children {
+			fmt.Fprintf(&b, "                %s)\n", child.word)
+			fmt.Fprintf(&b, "                    compadd -- %s ;;\n", c.words(child.cmd))
+		}
+		fmt.Fprintf(&b, "            esac ;;\n")
+	}
+	fmt.Fprintf(&b, "    esac\n")
+	fmt.Fprintf(&b, "}\n")
+	fmt.Fprintf(&b, "compdef _swo_cli %s\n", cliName)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (c *completionCommand) writeFish(w io.Writer) error {
+	nodes := c.nodes()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s\n", cliName)
+	fmt.Fprintf(&b, "complete -c %s -f\n", cliName)
+	fmt.Fprintf(&b, "complete -c %s -n __fish_use_subcommand -a \"%s\"\n", cliName, nodeWords(nodes))
+	for _, node := range nodes {
+		condition := "__fish_seen_subcommand_from " + node.word
+		if node.cmd != nil {
+			c.writeFishCommand(&b, condition, node.cmd)
+			continue
+		}
+
+		children := nodeWords(node.children)
+		fmt.Fprintf(&b, "complete -c %s -n \"%s; and not __fish_seen_subcommand_from %s\" -a \"%s\"\n", cliName, condition, children, children)
+		for _, child := range node.children {
+			c.writeFishCommand(&b, condition+"; and __fish_seen_subcommand_from "+child.word, child.cmd)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (c *completionCommand) writeFishCommand(b *strings.Builder, condition string, cmd Command) {
+	condition = fmt.Sprintf("-n \"%s\"", condition)
+	if args := c.args(cmd); len(args) > 0 {
+		fmt.Fprintf(b, "complete -c %s %s -a \"%s\"\n", cliName, condition, strings.Join(args, " "))
+	}
+
+	for _, f := range completionFlags(cmd.Help().fs) {
+		option := "-l " + f.name
+		if len(f.name) == 1 {
+			option = "-s " + f.name
+		}
+
+		switch {
+		case !f.value:
+			fmt.Fprintf(b, "complete -c %s %s %s\n", cliName, condition, option)
+		case completionValues[f.name] != nil:
+			fmt.Fprintf(b, "complete -c %s %s %s -x -a \"(%s completion values %s 2>/dev/null)\"\n", cliName, condition, option, cliName, f.name)
+		case slices.Contains(fileFlags, f.name):
+			fmt.Fprintf(b, "complete -c %s %s %s -r -F\n", cliName, condition, option)
+		default:
+			fmt.Fprintf(b, "complete -c %s %s %s -x\n", cliName, condition, option)
+		}
+	}
+}
+
+func (c *completionCommand) Name() string {
+	return completionCommandName
+}
+
+func (c *completionCommand) Usage() {
+	c.fs.Usage()
+}
+
+func (c *completionCommand) Help() *commandHelp {
+	return c.help
+}
@@ -0,0 +1,83 @@
+package logs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func completionScript(t *testing.T, shell string) string {
+	registry := NewRegistry()
+	registry.Register(NewLogsCommand())
+	registry.Register(NewStatsCommand())
+	registry.Register(NewHistogramCommand(), "hist")
+	registry.Register(NewCacheCommand())
+	registry.Register(NewConfigShowCommand())
+	cmd := NewCompletionCommand(registry)
+	registry.Register(cmd)
+	require.NoError(t, cmd.Init([]string{shell}))
+
+	var script strings.Builder
+	require.NoError(t, cmd.writeScript(&script))
+
+	return script.String()
+}
+
+func TestCompletionInit(t *testing.T) {
+	err := NewCompletionCommand(NewRegistry()).Init(nil)
+	require.True(t, errors.Is(err, errCompletionShell), "error: %v", err)
+
+	err = NewCompletionCommand(NewRegistry()).Init([]string{"powershell"})
+	require.True(t, errors.Is(err, errCompletionShell), "error: %v", err)
+
+	err = NewCompletionCommand(NewRegistry()).Init([]string{completionValuesArg, "count"})
+	require.True(t, errors.Is(err, errCompletionValue), "error: %v", err)
+
+	cmd := NewCompletionCommand(NewRegistry())
+	require.NoError(t, cmd.Init([]string{completionValuesArg, "color"}))
+	require.Equal(t, "color", cmd.flag)
+	require.Equal(t, []string{program, system, all, off}, completionValues[cmd.flag]())
+}
+
+func TestCompletionBash(t *testing.T) {
+	script := completionScript(t, "bash")
+
+	require.Contains(t, script, `compgen -W "logs stats histogram hist cache config completion"`)
+	require.Contains(t, script, "        --color)\n")
+	require.Contains(t, script, "swo-cli completion values color")
+	require.Contains(t, script, "swo-cli completion values by")
+	require.Contains(t, script, "        --profile)\n")
+	require.Contains(t, script, "swo-cli completion values system")
+	require.Contains(t, script, "-c|--ca-file|--cert-file|--configfile|")
+	require.Contains(t, script, `compgen -W "clear"`)
+	require.Contains(t, script, `compgen -W "show"`)
+	require.Contains(t, script, `case "${COMP_WORDS[2]}" in`)
+	require.Contains(t, script, `compgen -W "bash zsh fish `)
+	require.Contains(t, script, " --count ")
+	require.Contains(t, script, "complete -F _swo_cli swo-cli\n")
+}
+
+func TestCompletionZsh(t *testing.T) {
+	script := completionScript(t, "zsh")
+
+	require.True(t, strings.HasPrefix(script, "#compdef swo-cli\n"))
+	require.Contains(t, script, "compadd -- logs stats histogram hist cache config completion\n")
+	require.Contains(t, script, "swo-cli completion values color")
+	require.Contains(t, script, "_files")
+	require.Contains(t, script, "                compadd -- clear\n")
+	require.Contains(t, script, "case ${words[3]} in")
+}
+
+func TestCompletionFish(t *testing.T) {
+	script := completionScript(t, "fish")
+
+	require.Contains(t, script, `complete -c swo-cli -n __fish_use_subcommand -a "logs stats histogram hist cache config completion"`)
+	require.Contains(t, script, `complete -c swo-cli -n "__fish_seen_subcommand_from logs" -l color -x -a "(swo-cli completion values color 2>/dev/null)"`)
+	require.Contains(t, script, `complete -c swo-cli -n "__fish_seen_subcommand_from logs" -s c -r -F`)
+	require.Contains(t, script, `complete -c swo-cli -n "__fish_seen_subcommand_from logs" -l json`+"\n")
+	require.Contains(t, script, `complete -c swo-cli -n "__fish_seen_subcommand_from cache; and not __fish_seen_subcommand_from clear" -a "clear"`)
+	require.Contains(t, script, `complete -c swo-cli -n "__fish_seen_subcommand_from config; and __fish_seen_subcommand_from show" -s c -r -F`)
+	require.Contains(t, script, `complete -c swo-cli -n "__fish_seen_subcommand_from hist" -l by-severity`+"\n")
+}
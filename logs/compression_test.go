@@ -0,0 +1,72 @@
+package logs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchCompressed(t *testing.T) {
+	data, err := json.Marshal(logsData)
+	require.NoError(t, err)
+
+	encoders := map[string]func([]byte) []byte{
+		"identity": func(b []byte) []byte { return b },
+		"gzip": func(b []byte) []byte {
+			buf := &bytes.Buffer{}
+			w := gzip.NewWriter(buf)
+			_, err := w.Write(b)
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+			return buf.Bytes()
+		},
+		"zstd": func(b []byte) []byte {
+			encoder, err := zstd.NewWriter(nil)
+			require.NoError(t, err)
+			return encoder.EncodeAll(b, nil)
+		},
+	}
+
+	for encoding, encode := range encoders {
+		t.Run(encoding, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, acceptEncoding, r.Header.Get("Accept-Encoding"))
+
+				w.Header().Set("Content-Encoding", encoding)
+				_, err := w.Write(encode(data))
+				require.NoError(t, err)
+			}))
+			t.Cleanup(server.Close)
+
+			client, err := NewClient(WithToken("1234567"), WithApiUrl(server.URL))
+			require.NoError(t, err)
+
+			result, err := client.Search(context.Background(), Query{})
+			require.NoError(t, err)
+			require.Len(t, result.Logs, len(logsData.Logs))
+			require.Equal(t, logsData.Logs[0].Message, result.Logs[0].Message)
+			require.Equal(t, logsData.PageInfo, result.PageInfo)
+		})
+	}
+}
+
+func TestSearchUnsupportedEncoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(WithToken("1234567"), WithApiUrl(server.URL))
+	require.NoError(t, err)
+
+	_, err = client.Search(context.Background(), Query{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unsupported content encoding "br"`)
+}
@@ -0,0 +1,282 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const configShowCommandName = "config show"
+
+var (
+	errUnknownProfile = errors.New("unknown profile")
+	errConfigArgs     = errors.New("config show takes no arguments")
+)
+
+// profiles holds the profiles section of the config file. Each profile
+// overrides the top-level settings with its own, e.g.
+//
+//	token: 123456
+//	profiles:
+//	  eu:
+//	    token: 654321
+//	    api-url: https://api.eu-01.cloud.solarwinds.com
+type profiles struct {
+	Profiles map[string]yaml.Node `yaml:"profiles"`
+}
+
+// configPath returns the config file that is read: .swo-cli.yaml in the
+// working directory if there is one, otherwise file with ~/ expanded.
+func configPath(file string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	localConfig := filepath.Join(cwd, ".swo-cli.yaml")
+	if _, err := os.Stat(localConfig); err == nil {
+		return localConfig, nil
+	}
+
+	if strings.HasPrefix(file, "~/") {
+		usr, err := user.Current()
+		if err != nil {
+			return "", fmt.Errorf("error while resolving current user to read configuration file: %w", err)
+		}
+
+		return filepath.Join(usr.HomeDir, file[2:]), nil
+	}
+
+	return file, nil
+}
+
+// loadConfig reads the config file and the settings of the --profile into
+// the fields of opts whose flag was not given, then the SWOKEN environment
+// variable. It returns the path of the config file.
+func (opts *Options) loadConfig() (string, error) {
+	path, err := configPath(opts.configFile)
+	if err != nil {
+		return "", err
+	}
+
+	var config Options
+	var p profiles
+	if content, err := os.ReadFile(path); err == nil {
+		if err := yaml.Unmarshal(content, &config); err != nil {
+			return "", fmt.Errorf("error while unmarshaling %s config file: %w", path, err)
+		}
+		if err := yaml.Unmarshal(content, &p); err != nil {
+			return "", fmt.Errorf("error while unmarshaling %s config file: %w", path, err)
+		}
+	}
+
+	if globals.profile != "" {
+		node, ok := p.Profiles[globals.profile]
+		if !ok {
+			return "", fmt.Errorf("%w %q in %s", errUnknownProfile, globals.profile, path)
+		}
+
+		if err := node.Decode(&config); err != nil {
+			return "", fmt.Errorf("error while unmarshaling profile %s of %s config file: %w", globals.profile, path, err)
+		}
+	}
+
+	opts.applyConfig(&config)
+
+	if token := os.Getenv("SWOKEN"); token != "" {
+		opts.Token = token
+	}
+
+	return path, nil
+}
+
+// applyConfig copies the settings of config, except those given as flags.
+func (opts *Options) applyConfig(config *Options) {
+	set := map[string]bool{}
+	if opts.flags != nil {
+		opts.flags.Visit(func(f *flag.Flag) {
+			set[f.Name] = true
+		})
+	}
+
+	if config.ApiUrl != "" && !set["api-url"] {
+		opts.ApiUrl = config.ApiUrl
+	}
+	if config.Token != "" {
+		opts.Token = config.Token
+	}
+	if config.Timeout != 0 && !set["timeout"] {
+		opts.Timeout = config.Timeout
+	}
+	if config.DialTimeout != 0 && !set["dial-timeout"] {
+		opts.DialTimeout = config.DialTimeout
+	}
+	if config.Proxy != "" && !set["proxy"] {
+		opts.Proxy = config.Proxy
+	}
+	if config.CAFile != "" && !set["ca-file"] {
+		opts.CAFile = config.CAFile
+	}
+	if config.CertFile != "" && !set["cert-file"] {
+		opts.CertFile = config.CertFile
+	}
+	if config.KeyFile != "" && !set["key-file"] {
+		opts.KeyFile = config.KeyFile
+	}
+	if config.Insecure && !set["insecure"] {
+		opts.Insecure = config.Insecure
+	}
+	if config.UserAgentSuffix != "" && !set["user-agent-suffix"] {
+		opts.UserAgentSuffix = config.UserAgentSuffix
+	}
+	if config.RateLimit != 0 && !set["rate-limit"] {
+		opts.RateLimit = config.RateLimit
+	}
+	if config.CacheMaxSize != "" {
+		opts.CacheMaxSize = config.CacheMaxSize
+	}
+}
+
+// profileNames returns the profiles of the default config file.
+func profileNames() []string {
+	path, err := configPath(defaultConfigFile)
+	if err != nil {
+		return nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var p profiles
+	if err := yaml.Unmarshal(content, &p); err != nil {
+		return nil
+	}
+
+	return sortedKeys(p.Profiles)
+}
+
+// maskToken hides all but the last four characters of token, and all of a
+// short one.
+func maskToken(token string) string {
+	if len(token) <= 8 {
+		return strings.Repeat("*", len(token))
+	}
+
+	return strings.Repeat("*", len(token)-4) + token[len(token)-4:]
+}
+
+type configShowCommand struct {
+	fs   *flag.FlagSet
+	help *commandHelp
+	opts *Options
+	path string
+}
+
+func NewConfigShowCommand() *configShowCommand {
+	help := newCommandHelp(configShowCommandName, "print the effective configuration", "")
+	cmd := &configShowCommand{
+		fs:   help.fs,
+		help: help,
+		opts: &Options{ApiUrl: defaultApiUrl},
+	}
+
+	help.stringVar(&cmd.opts.configFile, "c,configfile", defaultConfigFile, "PATH", "Path to config")
+
+	help.example(
+		"swo-cli config show",
+		"swo-cli --profile eu config show",
+	)
+
+	return cmd
+}
+
+func (c *configShowCommand) Init(args []string) error {
+	err := c.help.parse(args)
+	if err != nil {
+		return err
+	}
+
+	if c.fs.NArg() != 0 {
+		return errConfigArgs
+	}
+
+	c.path, err = c.opts.loadConfig()
+	return err
+}
+
+func (c *configShowCommand) Run(ctx context.Context) error {
+	path := c.path
+	if _, err := os.Stat(path); err != nil {
+		path += " (not found)"
+	}
+
+	profile := globals.profile
+	if profile == "" {
+		profile = "(none)"
+	}
+
+	token := maskToken(c.opts.Token)
+	if token == "" {
+		token = "(not set)"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "config file:       %s\n", path)
+	fmt.Fprintf(&b, "profile:           %s\n", profile)
+	fmt.Fprintf(&b, "api-url:           %s\n", c.opts.ApiUrl)
+	fmt.Fprintf(&b, "token:             %s\n", token)
+	if c.opts.Timeout > 0 {
+		fmt.Fprintf(&b, "timeout:           %s\n", c.opts.Timeout)
+	}
+	if c.opts.DialTimeout > 0 {
+		fmt.Fprintf(&b, "dial-timeout:      %s\n", c.opts.DialTimeout)
+	}
+	if c.opts.Proxy != "" {
+		fmt.Fprintf(&b, "proxy:             %s\n", c.opts.Proxy)
+	}
+	if c.opts.CAFile != "" {
+		fmt.Fprintf(&b, "ca-file:           %s\n", c.opts.CAFile)
+	}
+	if c.opts.CertFile != "" {
+		fmt.Fprintf(&b, "cert-file:         %s\n", c.opts.CertFile)
+	}
+	if c.opts.KeyFile != "" {
+		fmt.Fprintf(&b, "key-file:          %s\n", c.opts.KeyFile)
+	}
+	if c.opts.Insecure {
+		fmt.Fprintf(&b, "insecure:          true\n")
+	}
+	if c.opts.UserAgentSuffix != "" {
+		fmt.Fprintf(&b, "user-agent-suffix: %s\n", c.opts.UserAgentSuffix)
+	}
+	if c.opts.RateLimit > 0 {
+		fmt.Fprintf(&b, "rate-limit:        %g\n", c.opts.RateLimit)
+	}
+	if c.opts.CacheMaxSize != "" {
+		fmt.Fprintf(&b, "cache-max-size:    %s\n", c.opts.CacheMaxSize)
+	}
+
+	_, err := os.Stdout.WriteString(b.String())
+	return err
+}
+
+func (c *configShowCommand) Name() string {
+	return configShowCommandName
+}
+
+func (c *configShowCommand) Usage() {
+	c.fs.Usage()
+}
+
+func (c *configShowCommand) Help() *commandHelp {
+	return c.help
+}
@@ -0,0 +1,156 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+const (
+	defaultContextWindow = time.Minute
+	contextPageSize      = 1000
+	contextSeparator     = "--"
+
+	// contextMaxPages limits the pages fetched for the window of one match.
+	contextMaxPages = 10
+)
+
+// contextEnabled reports whether -A, -B or -C was requested.
+func (opts *Options) contextEnabled() bool {
+	return opts.after > 0 || opts.before > 0
+}
+
+func (s *searcher) contextQuery(match Log) Query {
+	return Query{
+		Filter:    fmt.Sprintf("host:%s", match.Hostname),
+		Group:     s.opts.group,
+		StartTime: match.Time.Add(-s.opts.contextWindow),
+		EndTime:   match.Time.Add(s.opts.contextWindow),
+		PageSize:  contextPageSize,
+	}
+}
+
+// fetchContext returns the match surrounded by up to --before older and
+// --after newer logs of the same hostname and program, newest first like
+// the SWO API orders them. The window is paged through until it holds
+// --before logs older than the match.
+func (s *searcher) fetchContext(ctx context.Context, match Log) (*LogsData, error) {
+	q := s.contextQuery(match)
+
+	var neighbours []Log
+	idx := -1
+	for page := 0; ; page++ {
+		window, err := s.fetchPage(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, l := range window.Logs {
+			if l.Hostname == match.Hostname && l.Program == match.Program {
+				neighbours = append(neighbours, l)
+			}
+		}
+
+		slices.SortStableFunc(neighbours, func(a, b Log) int {
+			return b.Time.Compare(a.Time)
+		})
+
+		idx = slices.IndexFunc(neighbours, func(l Log) bool {
+			return sameLog(l, match)
+		})
+		if idx != -1 && len(neighbours)-idx-1 >= s.opts.before {
+			break
+		}
+		if window.NextPage == "" || len(window.Logs) == 0 {
+			break
+		}
+		if page+1 == contextMaxPages {
+			slog.Warn("Context window has too many logs, older context lines may be missing",
+				slog.String("hostname", match.Hostname),
+				slog.Time("time", match.Time),
+			)
+			break
+		}
+
+		q.Cursor = window.NextPage
+	}
+
+	if idx == -1 {
+		return &LogsData{Logs: []Log{match}}, nil
+	}
+
+	start := max(idx-s.opts.after, 0)
+	end := min(idx+s.opts.before+1, len(neighbours))
+
+	return &LogsData{Logs: neighbours[start:end]}, nil
+}
+
+// printWithContext prints every match together with its neighbouring logs,
+// oldest match first, separating the groups like grep does. Groups that
+// share logs are merged, so no log is printed twice.
+func (s *searcher) printWithContext(ctx context.Context, matches *LogsData) error {
+	var current *LogsData
+	printed := false
+	flush := func() error {
+		if current == nil {
+			return nil
+		}
+
+		if printed && !s.opts.json {
+			if _, err := fmt.Fprintln(s.output, contextSeparator); err != nil {
+				return err
+			}
+		}
+		printed = true
+
+		return s.printResult(current)
+	}
+
+	for i := len(matches.Logs) - 1; i >= 0; i-- {
+		group, err := s.fetchContext(ctx, matches.Logs[i])
+		if err != nil {
+			return err
+		}
+
+		if current != nil && overlaps(current.Logs, group.Logs) {
+			current = &LogsData{Logs: mergeLogs(current.Logs, group.Logs)}
+			continue
+		}
+
+		if err := flush(); err != nil {
+			return err
+		}
+		current = group
+	}
+
+	return flush()
+}
+
+func overlaps(a, b []Log) bool {
+	return slices.ContainsFunc(a, func(l Log) bool {
+		return slices.ContainsFunc(b, func(other Log) bool { return sameLog(l, other) })
+	})
+}
+
+// mergeLogs returns the logs of a and b without duplicates, newest first.
+func mergeLogs(a, b []Log) []Log {
+	merged := slices.Clone(a)
+	for _, l := range b {
+		if !slices.ContainsFunc(merged, func(other Log) bool { return sameLog(l, other) }) {
+			merged = append(merged, l)
+		}
+	}
+
+	slices.SortStableFunc(merged, func(x, y Log) int {
+		return y.Time.Compare(x.Time)
+	})
+
+	return merged
+}
+
+func sameLog(a, b Log) bool {
+	return a.Time.Equal(b.Time) && a.Message == b.Message && a.Hostname == b.Hostname &&
+		a.Program == b.Program && a.Severity == b.Severity
+}
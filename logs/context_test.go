@@ -0,0 +1,162 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchContext(t *testing.T) {
+	base, err := time.Parse(time.RFC3339, "2000-01-01T10:00:00Z")
+	require.NoError(t, err)
+
+	at := func(seconds int, program, message string) Log {
+		return Log{Time: base.Add(time.Duration(seconds) * time.Second), Hostname: "www42", Program: program, Message: message}
+	}
+
+	window := LogsData{
+		Logs: []Log{
+			at(4, "nginx", "after three"),
+			at(3, "nginx", "after two"),
+			at(2, "sshd", "other program"),
+			at(2, "nginx", "after one"),
+			at(1, "nginx", "panic: boom"),
+			at(0, "nginx", "before one"),
+			at(-1, "nginx", "before two"),
+		},
+	}
+
+	var query map[string][]string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/logs", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		data, err := json.Marshal(window)
+		require.NoError(t, err)
+
+		_, err = w.Write(data)
+		require.NoError(t, err)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	createConfigFile(t, configFile, fmt.Sprintf("token: 1234567\napi-url: %s", server.URL))
+
+	cmd := NewLogsCommand()
+	err = cmd.Init([]string{"--configfile", configFile, "--grep", "panic", "-A", "2", "-B", "1", "--context-window", "10s"})
+	require.NoError(t, err)
+
+	group, err := cmd.search.fetchContext(context.Background(), at(1, "nginx", "panic: boom"))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"host:www42"}, query["filter"])
+	require.Equal(t, []string{"2000-01-01T09:59:51Z"}, query["startTime"])
+	require.Equal(t, []string{"2000-01-01T10:00:11Z"}, query["endTime"])
+
+	var messages []string
+	for _, l := range group.Logs {
+		messages = append(messages, l.Message)
+	}
+	require.Equal(t, []string{"after two", "after one", "panic: boom", "before one"}, messages)
+}
+
+func TestContextOptions(t *testing.T) {
+	createConfigFile(t, configFile, "token: 1234567")
+
+	cmd := NewLogsCommand()
+	err := cmd.Init([]string{"--configfile", configFile, "-C", "3", "--grep", "error"})
+	require.NoError(t, err)
+	require.Equal(t, 3, cmd.opts.after)
+	require.Equal(t, 3, cmd.opts.before)
+	require.Equal(t, defaultContextWindow, cmd.opts.contextWindow)
+
+	cmd = NewLogsCommand()
+	err = cmd.Init([]string{"--configfile", configFile, "-C", "3"})
+	require.True(t, errors.Is(err, errContextFlag), "error: %v", err)
+}
+
+func TestFetchContextPages(t *testing.T) {
+	base, err := time.Parse(time.RFC3339, "2000-01-01T10:00:00Z")
+	require.NoError(t, err)
+
+	at := func(seconds int, message string) Log {
+		return Log{Time: base.Add(time.Duration(seconds) * time.Second), Hostname: "www42", Program: "nginx", Message: message}
+	}
+
+	pages := []LogsData{
+		{Logs: []Log{at(2, "after one"), at(1, "panic: boom")}, PageInfo: PageInfo{NextPage: "/v1/logs?page=1"}},
+		{Logs: []Log{at(0, "before one"), at(-1, "before two")}, PageInfo: PageInfo{NextPage: "/v1/logs?page=2"}},
+		{Logs: []Log{at(-2, "before three")}},
+	}
+
+	requests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/logs", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page := 0
+		fmt.Sscan(r.URL.Query().Get("page"), &page)
+		require.NoError(t, json.NewEncoder(w).Encode(pages[page]))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	createConfigFile(t, configFile, fmt.Sprintf("token: 1234567\napi-url: %s", server.URL))
+
+	cmd := NewLogsCommand()
+	require.NoError(t, cmd.Init([]string{"--configfile", configFile, "--grep", "panic", "-B", "2"}))
+
+	group, err := cmd.search.fetchContext(context.Background(), at(1, "panic: boom"))
+	require.NoError(t, err)
+	require.Equal(t, 2, requests)
+
+	var messages []string
+	for _, l := range group.Logs {
+		messages = append(messages, l.Message)
+	}
+	require.Equal(t, []string{"panic: boom", "before one", "before two"}, messages)
+}
+
+func TestPrintWithContextMerges(t *testing.T) {
+	base, err := time.Parse(time.RFC3339, "2000-01-01T10:00:00Z")
+	require.NoError(t, err)
+
+	at := func(seconds int, message string) Log {
+		return Log{Time: base.Add(time.Duration(seconds) * time.Second), Hostname: "www42", Program: "nginx", Message: message}
+	}
+
+	window := LogsData{Logs: []Log{at(60, "far panic"), at(30, "thirty"), at(4, "four"), at(3, "second panic"), at(2, "two"), at(1, "first panic"), at(0, "zero")}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/logs", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewEncoder(w).Encode(window))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	createConfigFile(t, configFile, fmt.Sprintf("token: 1234567\napi-url: %s", server.URL))
+
+	cmd := NewLogsCommand()
+	require.NoError(t, cmd.Init([]string{"--configfile", configFile, "--grep", "panic", "-C", "1"}))
+
+	var output strings.Builder
+	cmd.search.output = &output
+	cmd.search.hostnameColorIdx, cmd.search.programColorIdx = -1, -1
+
+	matches := &LogsData{Logs: []Log{at(60, "far panic"), at(3, "second panic"), at(1, "first panic")}}
+	require.NoError(t, cmd.search.printWithContext(context.Background(), matches))
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n") {
+		if line != contextSeparator {
+			line = line[strings.LastIndex(line, " ")+1:]
+		}
+		lines = append(lines, line)
+	}
+	require.Equal(t, []string{"zero", "panic", "two", "panic", "four", contextSeparator, "thirty", "panic"}, lines)
+}
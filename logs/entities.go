@@ -0,0 +1,352 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	entitiesCommandName = "entities"
+
+	defaultEntityType  = "Host"
+	defaultEntityCount = 100
+
+	maxEntityNames   = 5000
+	entitiesPageSize = 100
+)
+
+var errEntitiesArgs = errors.New("expected at most one entity name")
+
+type Entity struct {
+	ID           string            `json:"id"`
+	Type         string            `json:"type"`
+	Name         string            `json:"name"`
+	DisplayName  string            `json:"displayName,omitempty"`
+	CreatedTime  time.Time         `json:"createdTime"`
+	UpdatedTime  time.Time         `json:"updatedTime"`
+	LastSeenTime time.Time         `json:"lastSeenTime"`
+	Attributes   map[string]any    `json:"attributes,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+}
+
+type EntitiesData struct {
+	Entities []Entity `json:"entities"`
+	PageInfo `json:"pageInfo"`
+}
+
+// EntityQuery describes a single page of entities.
+type EntityQuery struct {
+	Type     string
+	Name     string
+	PageSize int
+
+	// Cursor continues a previous listing from its EntitiesData.NextPage.
+	Cursor string
+}
+
+// Entities fetches one page of the entities of a type, optionally only
+// those called q.Name.
+func (c *Client) Entities(ctx context.Context, q EntityQuery) (*EntitiesData, error) {
+	request, err := c.prepareEntitiesRequest(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("error while preparing http request to SWO: %w", err)
+	}
+
+	var entities EntitiesData
+	if err := c.get(request, &entities); err != nil {
+		return nil, err
+	}
+
+	return &entities, nil
+}
+
+func (c *Client) prepareEntitiesRequest(ctx context.Context, q EntityQuery) (*http.Request, error) {
+	if q.Cursor != "" {
+		return c.prepareNextPageRequest(ctx, q.Cursor)
+	}
+
+	params := url.Values{}
+	params.Add("type", q.Type)
+	if q.Name != "" {
+		params.Add("name", q.Name)
+	}
+	if q.PageSize > 0 {
+		params.Add("pageSize", strconv.Itoa(q.PageSize))
+	}
+
+	endpoint, err := url.JoinPath(c.apiUrl, "v1/entities")
+	if err != nil {
+		return nil, err
+	}
+
+	return c.newRequest(ctx, endpoint+"?"+params.Encode())
+}
+
+// Entity fetches the details of the entity with the given ID.
+func (c *Client) Entity(ctx context.Context, id string) (*Entity, error) {
+	endpoint, err := url.JoinPath(c.apiUrl, "v1/entities", id)
+	if err != nil {
+		return nil, fmt.Errorf("error while preparing http request to SWO: %w", err)
+	}
+
+	request, err := c.newRequest(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error while preparing http request to SWO: %w", err)
+	}
+
+	var entity Entity
+	if err := c.get(request, &entity); err != nil {
+		return nil, err
+	}
+
+	return &entity, nil
+}
+
+type entitiesCommand struct {
+	fs      *flag.FlagSet
+	help    *commandHelp
+	opts    *Options
+	client  *Client
+	limiter *rateLimiter
+	output  *lineWriter
+
+	entityType string
+	id         string
+	name       string
+}
+
+func NewEntitiesCommand() *entitiesCommand {
+	help := newCommandHelp(entitiesCommandName, "list and search monitored entities, e.g. hosts", "[name]")
+	cmd := &entitiesCommand{
+		fs:   help.fs,
+		help: help,
+		opts: &Options{},
+	}
+
+	help.stringVar(&cmd.entityType, "t,type", defaultEntityType, "TYPE", "Type of the entities, e.g. Host or KubernetesCluster")
+	help.stringVar(&cmd.id, "id", "", "ID", "Show the details of the entity ID")
+	help.intVar(&cmd.opts.count, "count", defaultEntityCount, "NUMBER", "Number of entities to list")
+	help.boolVar(&cmd.opts.json, "j,json", "Output JSON data")
+	help.stringVar(&cmd.opts.configFile, "c,configfile", defaultConfigFile, "PATH", "Path to config")
+	registerOutputFlags(help, cmd.opts)
+	registerConnectionFlags(help, cmd.opts)
+
+	help.example(
+		"swo-cli entities",
+		"swo-cli entities web-01",
+		"swo-cli entities --type KubernetesCluster -j",
+		"swo-cli entities --id e-1234567890",
+	)
+
+	return cmd
+}
+
+func (c *entitiesCommand) Init(args []string) error {
+	err := c.help.parse(args)
+	if err != nil {
+		return err
+	}
+
+	if c.fs.NArg() > 1 {
+		return errEntitiesArgs
+	}
+	c.name = c.fs.Arg(0)
+
+	opts, err := c.opts.Init([]string{})
+	if err != nil {
+		return err
+	}
+
+	c.client, err = newAPIClient(opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.RateLimit > 0 {
+		c.limiter = newRateLimiter(opts.RateLimit)
+	}
+
+	c.output, err = newOutput(opts)
+	return err
+}
+
+func (c *entitiesCommand) Run(ctx context.Context) error {
+	if c.client == nil {
+		return fmt.Errorf("%s command was not initialized", entitiesCommandName)
+	}
+
+	var err error
+	if c.id != "" {
+		err = c.show(ctx)
+	} else {
+		err = c.list(ctx)
+	}
+
+	return errors.Join(err, c.output.Close())
+}
+
+func (c *entitiesCommand) show(ctx context.Context) error {
+	entity, err := c.client.Entity(ctx, c.id)
+	if err != nil {
+		return err
+	}
+
+	if c.opts.json {
+		jsonFormat, err := json.Marshal(entity)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(c.output, string(jsonFormat))
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "id:             %s\n", entity.ID)
+	fmt.Fprintf(&b, "type:           %s\n", entity.Type)
+	fmt.Fprintf(&b, "name:           %s\n", entity.Name)
+	if entity.DisplayName != "" {
+		fmt.Fprintf(&b, "display name:   %s\n", entity.DisplayName)
+	}
+	if !entity.CreatedTime.IsZero() {
+		fmt.Fprintf(&b, "created:        %s\n", entity.CreatedTime.Local().Format(time.RFC3339))
+	}
+	if !entity.LastSeenTime.IsZero() {
+		fmt.Fprintf(&b, "last seen:      %s\n", entity.LastSeenTime.Local().Format(time.RFC3339))
+	}
+	for _, key := range sortedKeys(entity.Tags) {
+		fmt.Fprintf(&b, "tag %s: %s\n", key, entity.Tags[key])
+	}
+	for _, key := range sortedKeys(entity.Attributes) {
+		fmt.Fprintf(&b, "attribute %s: %v\n", key, entity.Attributes[key])
+	}
+
+	_, err = fmt.Fprint(c.output, b.String())
+	return err
+}
+
+// list walks pages until --count entities were printed or there are no
+// more pages, and caches their names.
+func (c *entitiesCommand) list(ctx context.Context) error {
+	q := EntityQuery{
+		Type:     c.entityType,
+		Name:     c.name,
+		PageSize: min(c.opts.count, entitiesPageSize),
+	}
+
+	var result EntitiesData
+	for len(result.Entities) < c.opts.count {
+		if c.limiter != nil {
+			if err := c.limiter.wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		entities, err := c.client.Entities(ctx, q)
+		if err != nil {
+			return err
+		}
+
+		result.Entities = append(result.Entities, entities.Entities...)
+		result.PageInfo = entities.PageInfo
+		if entities.NextPage == "" || len(entities.Entities) == 0 {
+			break
+		}
+
+		q.Cursor = entities.NextPage
+	}
+
+	if len(result.Entities) > c.opts.count {
+		result.Entities = result.Entities[:c.opts.count]
+	}
+
+	if err := cacheEntityNames(c.opts.ApiUrl, c.opts.Token, result.Entities); err != nil {
+		slog.Warn("Could not cache entity names", slog.String("error", err.Error()))
+	}
+
+	if c.opts.json {
+		jsonFormat, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(c.output, string(jsonFormat))
+		return err
+	}
+
+	for _, entity := range result.Entities {
+		if _, err := fmt.Fprintf(c.output, "%s %s %s\n", entity.ID, entity.Type, entity.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// entityNamesFile returns the cache file of the names of listed entities,
+// for completing --system.
+func entityNamesFile(apiUrl, token string) string {
+	return "entity-names-" + accountKey(apiUrl, token)
+}
+
+// cacheEntityNames adds the names of entities to the cached ones of the
+// account, dropping the least recently listed beyond maxEntityNames.
+func cacheEntityNames(apiUrl, token string, entities []Entity) error {
+	names := cachedEntityNames(apiUrl, token)
+	for _, entity := range entities {
+		if entity.Name == "" {
+			continue
+		}
+
+		names = slices.DeleteFunc(names, func(name string) bool { return name == entity.Name })
+		names = append(names, entity.Name)
+	}
+
+	if len(names) > maxEntityNames {
+		names = names[len(names)-maxEntityNames:]
+	}
+
+	return writeCacheFile(entityNamesFile(apiUrl, token), names)
+}
+
+// cachedEntityNames returns the names of the entities of the account listed
+// so far, the most recently listed last.
+func cachedEntityNames(apiUrl, token string) []string {
+	var names []string
+	if err := readCacheFile(entityNamesFile(apiUrl, token), &names); err != nil {
+		return nil
+	}
+
+	return names
+}
+
+// completeEntityNames returns the cached entity names of the account of the
+// default config file.
+func completeEntityNames() []string {
+	names := cachedEntityNames(defaultAccount())
+	slices.Sort(names)
+
+	return names
+}
+
+func (c *entitiesCommand) Name() string {
+	return entitiesCommandName
+}
+
+func (c *entitiesCommand) Usage() {
+	c.fs.Usage()
+}
+
+func (c *entitiesCommand) Help() *commandHelp {
+	return c.help
+}
@@ -0,0 +1,104 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newEntitiesServer(t *testing.T, pages int, perPage int) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/entities", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Host", r.URL.Query().Get("type"))
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		var data EntitiesData
+		for i := 0; i < perPage; i++ {
+			name := fmt.Sprintf("web-%d%d", page, i)
+			if filter := r.URL.Query().Get("name"); filter != "" {
+				name = filter
+			}
+			data.Entities = append(data.Entities, Entity{ID: fmt.Sprintf("e-%d%d", page, i), Type: "Host", Name: name})
+		}
+		if page+1 < pages {
+			data.NextPage = fmt.Sprintf("/v1/entities?type=Host&page=%d", page+1)
+		}
+
+		require.NoError(t, json.NewEncoder(w).Encode(data))
+	})
+	mux.HandleFunc("/v1/entities/e-42", func(w http.ResponseWriter, r *http.Request) {
+		entity := Entity{ID: "e-42", Type: "Host", Name: "db-01", Tags: map[string]string{"env": "prod"}}
+		require.NoError(t, json.NewEncoder(w).Encode(entity))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func runEntities(t *testing.T, args ...string) string {
+	output := filepath.Join(t.TempDir(), "output.txt")
+	cmd := NewEntitiesCommand()
+	require.NoError(t, cmd.Init(append([]string{"--output-file", output}, args...)))
+	require.NoError(t, cmd.Run(context.Background()))
+
+	content, err := os.ReadFile(output)
+	require.NoError(t, err)
+
+	return string(content)
+}
+
+func TestEntitiesCommand(t *testing.T) {
+	server := newEntitiesServer(t, 3, 2)
+	createConfigFile(t, configFile, fmt.Sprintf("token: 1234567\napi-url: %s", server.URL))
+
+	output := runEntities(t, "-c", configFile, "--count", "3")
+	require.Equal(t, "e-00 Host web-00\ne-01 Host web-01\ne-10 Host web-10\n", output)
+	require.Equal(t, []string{"web-00", "web-01", "web-10"}, cachedEntityNames(server.URL, "1234567"))
+	require.Empty(t, cachedEntityNames(server.URL, "7654321"))
+
+	output = runEntities(t, "-c", configFile, "db-01", "-j")
+	var data EntitiesData
+	require.NoError(t, json.Unmarshal([]byte(output), &data))
+	require.Len(t, data.Entities, 6)
+	require.Equal(t, "db-01", data.Entities[0].Name)
+	require.Contains(t, cachedEntityNames(server.URL, "1234567"), "db-01")
+
+	output = runEntities(t, "-c", configFile, "--id", "e-42")
+	require.Contains(t, output, "name:           db-01\n")
+	require.Contains(t, output, "tag env: prod\n")
+}
+
+func TestEntitiesInit(t *testing.T) {
+	createConfigFile(t, configFile, "token: 1234567")
+
+	err := NewEntitiesCommand().Init([]string{"-c", configFile, "web-01", "web-02"})
+	require.True(t, errors.Is(err, errEntitiesArgs), "error: %v", err)
+}
+
+func TestCacheEntityNames(t *testing.T) {
+	apiUrl := "https://api.example.com"
+	var entities []Entity
+	for i := 0; i < maxEntityNames; i++ {
+		entities = append(entities, Entity{Name: fmt.Sprintf("host-%04d", i)})
+	}
+	require.NoError(t, cacheEntityNames(apiUrl, "1234567", entities))
+
+	// listing a known name again keeps it, the oldest one is dropped
+	require.NoError(t, cacheEntityNames(apiUrl, "1234567", []Entity{{Name: "host-0000"}, {Name: "a-new-host"}}))
+	names := cachedEntityNames(apiUrl, "1234567")
+	require.Len(t, names, maxEntityNames)
+	require.Equal(t, "host-0002", names[0])
+	require.Equal(t, []string{"host-0000", "a-new-host"}, names[len(names)-2:])
+	require.NotContains(t, names, "host-0001")
+}
@@ -0,0 +1,334 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+const (
+	exploreCommandName = "explore"
+
+	defaultFollowInterval = 5 * time.Second
+
+	enterAltScreen = "\x1b[?1049h\x1b[?25l"
+	leaveAltScreen = "\x1b[?25h\x1b[?1049l"
+)
+
+var errNotTerminal = errors.New("explore requires an interactive terminal")
+
+type exploreCommand struct {
+	fs     *flag.FlagSet
+	help   *commandHelp
+	search *searcher
+	opts   *Options
+
+	followInterval time.Duration
+}
+
+type exploreResult struct {
+	action     exploreAction
+	generation int
+	page       *LogsData
+	err        error
+}
+
+func NewExploreCommand() *exploreCommand {
+	help := newCommandHelp(exploreCommandName, "browse logs in a full-screen terminal UI", "[--] [query]")
+	cmd := &exploreCommand{
+		fs:   help.fs,
+		help: help,
+		opts: &Options{},
+	}
+
+	help.intVar(&cmd.opts.count, "count", defaultCount, "NUMBER", "Number of log entries fetched per page")
+	help.durationVar(&cmd.followInterval, "follow-interval", defaultFollowInterval, "DURATION", "How often new logs are fetched in follow mode")
+	registerSearchFlags(help, cmd.opts, "show")
+	registerConnectionFlags(help, cmd.opts)
+
+	help.section("Keys",
+		"up/down, pgup/pgdown, home/end   scroll; scrolling past the top loads older logs",
+		"/                                edit the query, enter to search, esc to cancel",
+		"enter                            show or hide details of the selected log",
+		"s, h                             cycle through the severity and host facets",
+		"f                                toggle following new logs",
+		"r                                search again",
+		"q                                quit",
+	)
+	help.example(
+		"swo-cli explore",
+		`swo-cli explore --min-time '1 hour ago' -s www42 "connection refused"`,
+	)
+
+	return cmd
+}
+
+func (c *exploreCommand) Init(args []string) error {
+	err := c.help.parse(args)
+	if err != nil {
+		return err
+	}
+
+	if c.followInterval <= 0 {
+		c.followInterval = defaultFollowInterval
+	}
+
+	opts, err := c.opts.Init(c.fs.Args())
+	if err != nil {
+		return err
+	}
+
+	search, err := newSearcher(opts)
+	if err != nil {
+		return err
+	}
+
+	c.search = search
+
+	return nil
+}
+
+func (c *exploreCommand) Run(ctx context.Context) error {
+	if c.search == nil {
+		return fmt.Errorf("%s command was not initialized", exploreCommandName)
+	}
+
+	err := c.run(ctx)
+
+	return errors.Join(err, c.search.Close())
+}
+
+func (c *exploreCommand) run(ctx context.Context) error {
+	in := int(os.Stdin.Fd())
+	out := int(os.Stdout.Fd())
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		return errNotTerminal
+	}
+
+	if err := c.search.resolveGroup(ctx); err != nil {
+		return err
+	}
+
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(in, state)
+
+	if _, err := io.WriteString(os.Stdout, enterAltScreen); err != nil {
+		return err
+	}
+	defer io.WriteString(os.Stdout, leaveAltScreen)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the reader stays blocked on stdin until the process exits
+	keys := make(chan string)
+	go readKeys(os.Stdin, keys)
+
+	results := make(chan exploreResult)
+	e := newExplorer(strings.Join(c.opts.args, " "))
+	c.fetch(ctx, e, searchAction, results)
+
+	ticker := time.NewTicker(c.followInterval)
+	defer ticker.Stop()
+
+	for {
+		width, height, err := term.GetSize(out)
+		if err != nil {
+			return err
+		}
+
+		if err := draw(os.Stdout, e.render(width, height)); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+
+			action := e.handleKey(key)
+			if action == quitAction {
+				return nil
+			}
+			if action != noAction {
+				c.fetch(ctx, e, action, results)
+			}
+		case result := <-results:
+			e.receive(result)
+		case <-ticker.C:
+			if e.follow && !e.loading && !e.editing {
+				e.loading = true
+				c.fetch(ctx, e, newerAction, results)
+			}
+		}
+	}
+}
+
+// fetch runs the query for action in the background and delivers the page,
+// without the logs rejected by the local filters, to results.
+func (c *exploreCommand) fetch(ctx context.Context, e *explorer, action exploreAction, results chan<- exploreResult) {
+	q, err := c.exploreQuery(e, action)
+	generation := e.generation
+
+	go func() {
+		var page *LogsData
+		if err == nil {
+			page, err = c.search.fetchPage(ctx, q)
+		}
+		if err == nil {
+			page.Logs = slices.DeleteFunc(page.Logs, func(l Log) bool {
+				return !matchesFilters(c.search.opts.filters, l)
+			})
+		}
+
+		select {
+		case results <- exploreResult{action: action, generation: generation, page: page, err: err}:
+		case <-ctx.Done():
+		}
+	}()
+}
+
+func (c *exploreCommand) exploreQuery(e *explorer, action exploreAction) (Query, error) {
+	c.opts.args = nil
+	if e.query != "" {
+		c.opts.args = []string{e.query}
+	}
+
+	q, err := c.search.query()
+	if err != nil {
+		return q, err
+	}
+
+	switch action {
+	case olderAction:
+		q.Cursor = e.nextPage
+	case newerAction:
+		if len(e.logs) > 0 {
+			q.StartTime = e.logs[len(e.logs)-1].Time
+		}
+		q.EndTime = time.Time{}
+	}
+
+	return q, nil
+}
+
+func draw(w io.Writer, lines []string) error {
+	var frame bytes.Buffer
+	frame.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i > 0 {
+			frame.WriteString("\r\n")
+		}
+
+		frame.WriteString(line)
+		frame.WriteString("\x1b[K")
+	}
+	frame.WriteString("\x1b[J")
+
+	_, err := w.Write(frame.Bytes())
+	return err
+}
+
+func readKeys(r io.Reader, keys chan<- string) {
+	defer close(keys)
+
+	buf := make([]byte, 256)
+	for {
+		n, err := r.Read(buf)
+		for _, key := range parseKeys(buf[:n]) {
+			keys <- key
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// parseKeys splits raw terminal input into key names such as "up",
+// "enter" or "ctrl-c"; printable characters are returned as they are.
+func parseKeys(input []byte) []string {
+	var keys []string
+	for i := 0; i < len(input); {
+		switch b := input[i]; {
+		case b == 0x1b && i+1 < len(input) && (input[i+1] == '[' || input[i+1] == 'O'):
+			end := i + 2
+			for end < len(input) && !(input[end] >= 0x40 && input[end] <= 0x7e) {
+				end++
+			}
+			if end < len(input) {
+				end++
+			}
+
+			keys = append(keys, csiKey(string(input[i+2:end])))
+			i = end
+		case b == 0x1b:
+			keys = append(keys, "esc")
+			i++
+		case b == '\r' || b == '\n':
+			keys = append(keys, "enter")
+			i++
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, "backspace")
+			i++
+		case b == 0x03:
+			keys = append(keys, "ctrl-c")
+			i++
+		case b < ' ':
+			i++
+		default:
+			r, size := utf8.DecodeRune(input[i:])
+			keys = append(keys, string(r))
+			i += size
+		}
+	}
+
+	return keys
+}
+
+func csiKey(sequence string) string {
+	switch sequence {
+	case "A":
+		return "up"
+	case "B":
+		return "down"
+	case "H", "1~", "7~":
+		return "home"
+	case "F", "4~", "8~":
+		return "end"
+	case "5~":
+		return "pgup"
+	case "6~":
+		return "pgdown"
+	default:
+		return ""
+	}
+}
+
+func (c *exploreCommand) Name() string {
+	return exploreCommandName
+}
+
+func (c *exploreCommand) Usage() {
+	c.fs.Usage()
+}
+
+func (c *exploreCommand) Help() *commandHelp {
+	return c.help
+}
@@ -0,0 +1,427 @@
+package logs
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+)
+
+type exploreAction int
+
+const (
+	noAction exploreAction = iota
+	quitAction
+	searchAction
+	olderAction
+	newerAction
+)
+
+const exploreDetailHeight = 8
+
+// explorer holds the state of the explore TUI. It only turns keys into
+// actions and state into screen lines; fetching and the terminal are
+// handled by exploreCommand.
+type explorer struct {
+	query    string
+	editing  bool
+	input    []rune
+	logs     []Log // oldest first, like the screen shows them
+	nextPage string
+	loading  bool
+	status   string
+
+	// generation counts the searches, so that pages fetched for an earlier
+	// query are dropped
+	generation int
+
+	selected int // index into visible()
+	offset   int // first visible() row on screen
+	rows     int // list rows of the last render
+
+	severity string
+	host     string
+	detail   bool
+	follow   bool
+}
+
+type facet struct {
+	value string
+	count int
+}
+
+func newExplorer(query string) *explorer {
+	return &explorer{query: query, loading: true}
+}
+
+// visible returns the loaded logs that pass the severity and host facets.
+func (e *explorer) visible() []Log {
+	var logs []Log
+	for _, l := range e.logs {
+		if e.severity != "" && l.Severity != e.severity {
+			continue
+		}
+		if e.host != "" && l.Hostname != e.host {
+			continue
+		}
+
+		logs = append(logs, l)
+	}
+
+	return logs
+}
+
+// facets counts the values of field among the loaded logs, most frequent
+// first.
+func (e *explorer) facets(field func(Log) string) []facet {
+	counts := map[string]int{}
+	for _, l := range e.logs {
+		counts[field(l)]++
+	}
+
+	var facets []facet
+	for value, count := range counts {
+		facets = append(facets, facet{value: value, count: count})
+	}
+
+	slices.SortFunc(facets, func(a, b facet) int {
+		if c := cmp.Compare(b.count, a.count); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.value, b.value)
+	})
+
+	return facets
+}
+
+// nextFacet cycles from all values through every facet value and back.
+func nextFacet(current string, facets []facet) string {
+	for i, f := range facets {
+		if f.value == current {
+			if i+1 < len(facets) {
+				return facets[i+1].value
+			}
+
+			return ""
+		}
+	}
+
+	if len(facets) > 0 {
+		return facets[0].value
+	}
+
+	return ""
+}
+
+func (e *explorer) handleKey(key string) exploreAction {
+	if e.editing {
+		return e.handleEditKey(key)
+	}
+
+	switch key {
+	case "q", "ctrl-c":
+		return quitAction
+	case "up", "k":
+		return e.move(-1)
+	case "down", "j":
+		return e.move(1)
+	case "pgup":
+		return e.move(-max(e.rows, 1))
+	case "pgdown":
+		return e.move(max(e.rows, 1))
+	case "home", "g":
+		return e.move(-len(e.logs))
+	case "end", "G":
+		return e.move(len(e.logs))
+	case "/":
+		e.editing = true
+		e.input = []rune(e.query)
+	case "enter":
+		e.detail = !e.detail
+	case "s":
+		e.severity = nextFacet(e.severity, e.facets(func(l Log) string { return l.Severity }))
+		e.selectLast()
+	case "h":
+		e.host = nextFacet(e.host, e.facets(func(l Log) string { return l.Hostname }))
+		e.selectLast()
+	case "f":
+		e.follow = !e.follow
+		if e.follow && !e.loading {
+			e.loading = true
+			return newerAction
+		}
+	case "r":
+		if !e.loading {
+			return e.search()
+		}
+	}
+
+	return noAction
+}
+
+func (e *explorer) handleEditKey(key string) exploreAction {
+	switch key {
+	case "ctrl-c":
+		return quitAction
+	case "esc":
+		e.editing = false
+	case "enter":
+		query := string(e.input)
+		if _, err := parseQuery(query); err != nil {
+			e.status = err.Error()
+			return noAction
+		}
+
+		e.editing = false
+		e.query = query
+		return e.search()
+	case "backspace":
+		if len(e.input) > 0 {
+			e.input = e.input[:len(e.input)-1]
+		}
+	default:
+		if utf8.RuneCountInString(key) == 1 {
+			e.input = append(e.input, []rune(key)...)
+		}
+	}
+
+	return noAction
+}
+
+// search starts a new query; pages of the previous ones still being
+// fetched are dropped.
+func (e *explorer) search() exploreAction {
+	e.generation++
+	e.loading = true
+	return searchAction
+}
+
+// move moves the selection by delta rows. Moving up past the oldest loaded
+// log loads the previous page.
+func (e *explorer) move(delta int) exploreAction {
+	count := len(e.visible())
+	if delta < 0 && e.selected+delta < 0 && e.nextPage != "" && !e.loading {
+		e.selected = 0
+		e.loading = true
+		return olderAction
+	}
+
+	e.selected = min(max(e.selected+delta, 0), max(count-1, 0))
+	return noAction
+}
+
+func (e *explorer) selectLast() {
+	e.selected = max(len(e.visible())-1, 0)
+}
+
+// receive applies a fetched page unless it belongs to an earlier query.
+func (e *explorer) receive(result exploreResult) {
+	if result.generation != e.generation {
+		return
+	}
+
+	e.apply(result.action, result.page, result.err)
+}
+
+// apply updates the state with a page fetched for action.
+func (e *explorer) apply(action exploreAction, page *LogsData, err error) {
+	e.loading = false
+	if err != nil {
+		e.status = err.Error()
+		return
+	}
+
+	e.status = ""
+	logs := slices.Clone(page.Logs)
+	slices.Reverse(logs)
+
+	switch action {
+	case searchAction:
+		e.logs = logs
+		e.nextPage = page.NextPage
+		e.offset = 0
+		e.selectLast()
+	case olderAction:
+		before := len(e.visible())
+		e.logs = append(logs, e.logs...)
+		e.nextPage = page.NextPage
+		added := len(e.visible()) - before
+		e.selected += added
+		e.offset += added
+		if len(logs) == 0 {
+			e.status = "no older logs"
+		}
+	case newerAction:
+		atEnd := e.selected >= len(e.visible())-1
+		for _, l := range logs {
+			if !slices.ContainsFunc(e.logs, func(known Log) bool { return sameLog(known, l) }) {
+				e.logs = append(e.logs, l)
+			}
+		}
+		if atEnd {
+			e.selectLast()
+		}
+	}
+}
+
+// render draws the screen as exactly height lines of at most width
+// characters.
+func (e *explorer) render(width, height int) []string {
+	logs := e.visible()
+
+	var lines []string
+	follow := "off"
+	if e.follow {
+		follow = "on"
+	}
+	lines = append(lines, fit(fmt.Sprintf("swo-cli explore | query: %s | follow: %s | %d/%d logs", e.query, follow, len(logs), len(e.logs)), width))
+	lines = append(lines, fit(fmt.Sprintf("severity: %s | host: %s",
+		facetLine(e.severity, e.facets(func(l Log) string { return l.Severity })),
+		facetLine(e.host, e.facets(func(l Log) string { return l.Hostname }))), width))
+
+	detailHeight := 0
+	if e.detail && len(logs) > 0 {
+		detailHeight = min(exploreDetailHeight, max(height-6, 0))
+	}
+
+	e.rows = max(height-len(lines)-1-detailHeight, 1)
+	if e.selected < e.offset {
+		e.offset = e.selected
+	}
+	if e.selected >= e.offset+e.rows {
+		e.offset = e.selected - e.rows + 1
+	}
+
+	for i := e.offset; i < e.offset+e.rows; i++ {
+		if i >= len(logs) {
+			lines = append(lines, "")
+			continue
+		}
+
+		line := fit(formatExploreLog(logs[i]), width)
+		if i == e.selected {
+			line = color.New(color.ReverseVideo).Sprint(line)
+		} else {
+			line = severityColor(logs[i].Severity)("%s", line)
+		}
+
+		lines = append(lines, line)
+	}
+
+	if detailHeight > 0 {
+		lines = append(lines, e.renderDetail(logs[e.selected], width, detailHeight)...)
+	}
+
+	lines = append(lines, fit(e.statusLine(), width))
+
+	return lines[:min(len(lines), height)]
+}
+
+func (e *explorer) renderDetail(l Log, width, height int) []string {
+	lines := []string{
+		strings.Repeat("-", width),
+		fit(fmt.Sprintf("time: %s  hostname: %s  program: %s  severity: %s", l.Time.Format(time.RFC3339Nano), l.Hostname, l.Program, l.Severity), width),
+	}
+	for _, line := range wrap(l.Message, width) {
+		if len(lines) == height {
+			break
+		}
+
+		lines = append(lines, line)
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+
+	return lines
+}
+
+func (e *explorer) statusLine() string {
+	switch {
+	case e.editing:
+		return "/" + string(e.input)
+	case e.status != "":
+		return e.status
+	case e.loading:
+		return "loading..."
+	default:
+		return "q quit  / query  enter details  s severity  h host  f follow  r refresh"
+	}
+}
+
+func formatExploreLog(l Log) string {
+	return fmt.Sprintf("%s %s %s %s", l.Time.Format("Jan 02 15:04:05"), l.Hostname, l.Program, l.Message)
+}
+
+func facetLine(selected string, facets []facet) string {
+	parts := []string{"all"}
+	for _, f := range facets {
+		value := f.value
+		if value == "" {
+			value = "-"
+		}
+
+		parts = append(parts, fmt.Sprintf("%s %d", value, f.count))
+	}
+
+	if selected == "" {
+		parts[0] = "[all]"
+	} else {
+		for i, f := range facets {
+			if f.value == selected {
+				parts[i+1] = "[" + parts[i+1] + "]"
+			}
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func severityColor(severity string) ColorStrFunc {
+	switch strings.ToUpper(severity) {
+	case "ERROR", "CRITICAL", "FATAL", "EMERGENCY", "ALERT":
+		return color.RedString
+	case "WARN", "WARNING":
+		return color.YellowString
+	default:
+		return fmt.Sprintf
+	}
+}
+
+// fit truncates s to width characters and replaces control characters so
+// that a log line cannot break the layout.
+func fit(s string, width int) string {
+	s = strings.Map(func(r rune) rune {
+		if r < ' ' {
+			return ' '
+		}
+
+		return r
+	}, s)
+
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+
+	return string([]rune(s)[:max(width, 0)])
+}
+
+func wrap(s string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		runes := []rune(fit(paragraph, len(paragraph)))
+		for len(runes) > width && width > 0 {
+			lines = append(lines, string(runes[:width]))
+			runes = runes[width:]
+		}
+
+		lines = append(lines, string(runes))
+	}
+
+	return lines
+}
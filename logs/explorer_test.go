@@ -0,0 +1,191 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+)
+
+func explorePage(base time.Time, nextPage string, entries ...Log) *LogsData {
+	page := &LogsData{PageInfo: PageInfo{NextPage: nextPage}}
+	for i, l := range entries {
+		l.Time = base.Add(-time.Duration(i) * time.Minute)
+		page.Logs = append(page.Logs, l)
+	}
+
+	return page
+}
+
+func TestParseKeys(t *testing.T) {
+	require.Equal(t,
+		[]string{"up", "down", "pgup", "pgdown", "home", "end", "esc", "enter", "backspace", "ctrl-c", "q", "ü"},
+		parseKeys([]byte("\x1b[A\x1bOB\x1b[5~\x1b[6~\x1b[H\x1b[4~\x1b\r\x7f\x03qü")))
+}
+
+func TestExplorer(t *testing.T) {
+	color.NoColor = true
+
+	base, err := time.Parse(time.RFC3339, "2000-01-01T10:00:00Z")
+	require.NoError(t, err)
+
+	e := newExplorer("error")
+	e.apply(searchAction, explorePage(base, "/v1/logs?skipToken=older",
+		Log{Message: "third", Hostname: "www42", Severity: "ERROR"},
+		Log{Message: "second", Hostname: "db01", Severity: "INFO"},
+		Log{Message: "first", Hostname: "www42", Severity: "INFO"},
+	), nil)
+
+	require.Equal(t, []string{
+		"swo-cli explore | query: error | follow: off | 3/3 logs",
+		"severity: [all] INFO 2 ERROR 1 | host: [all] www42 2 db01 1",
+		"Jan 01 09:58:00 www42  first",
+		"Jan 01 09:59:00 db01  second",
+		"Jan 01 10:00:00 www42  third",
+		"",
+		e.statusLine(),
+	}, e.render(80, 7))
+	require.Equal(t, 2, e.selected)
+
+	// scrolling past the oldest log loads the previous page
+	require.Equal(t, noAction, e.handleKey("up"))
+	require.Equal(t, noAction, e.handleKey("k"))
+	require.Equal(t, olderAction, e.handleKey("up"))
+	require.Equal(t, noAction, e.handleKey("up"))
+	e.apply(olderAction, explorePage(base.Add(-3*time.Minute), "", Log{Message: "zeroth", Hostname: "www42", Severity: "WARN"}), nil)
+	require.Equal(t, 1, e.selected)
+	require.Equal(t, "first", e.visible()[e.selected].Message)
+	require.Equal(t, noAction, e.handleKey("up"))
+	require.Equal(t, noAction, e.handleKey("up"))
+	require.Equal(t, 0, e.selected)
+
+	// facets
+	require.Equal(t, noAction, e.handleKey("s"))
+	require.Equal(t, "INFO", e.severity)
+	require.Len(t, e.visible(), 2)
+	require.Equal(t, 1, e.selected)
+	e.handleKey("h")
+	require.Equal(t, "www42", e.host)
+	require.Equal(t, []string{"first"}, messages(e.visible()))
+	e.handleKey("s")
+	e.handleKey("s")
+	e.handleKey("s")
+	e.handleKey("h")
+	e.handleKey("h")
+	require.Empty(t, e.severity)
+	require.Empty(t, e.host)
+
+	// detail pane of the selected log
+	e.handleKey("end")
+	e.handleKey("enter")
+	lines := e.render(40, 16)
+	require.Len(t, lines, 16)
+	require.Equal(t, strings.Repeat("-", 40), lines[7])
+	require.Contains(t, lines[8], "time: 2000-01-01T10:00:00Z")
+	require.Equal(t, "third", lines[9])
+
+	// follow appends new logs and keeps the newest selected
+	require.Equal(t, newerAction, e.handleKey("f"))
+	e.apply(newerAction, explorePage(base.Add(time.Minute), "", Log{Message: "fourth"}, Log{Message: "third", Hostname: "www42", Severity: "ERROR"}), nil)
+	require.Equal(t, []string{"zeroth", "first", "second", "third", "fourth"}, messages(e.visible()))
+	require.Equal(t, 4, e.selected)
+
+	// query editing
+	require.Equal(t, noAction, e.handleKey("/"))
+	for _, key := range []string{"backspace", "backspace", "backspace", "backspace", "backspace", "(", "x"} {
+		require.Equal(t, noAction, e.handleKey(key))
+	}
+	require.Equal(t, "/(x", e.statusLine())
+	require.Equal(t, noAction, e.handleKey("enter"))
+	require.Contains(t, e.status, "unbalanced opening parenthesis")
+	e.handleKey(")")
+	require.Equal(t, searchAction, e.handleKey("enter"))
+	require.Equal(t, "(x)", e.query)
+	require.False(t, e.editing)
+
+	e.apply(searchAction, nil, errNotTerminal)
+	require.Equal(t, errNotTerminal.Error(), e.status)
+	require.Equal(t, quitAction, e.handleKey("q"))
+}
+
+func TestExplorerDropsStaleResults(t *testing.T) {
+	base, err := time.Parse(time.RFC3339, "2000-01-01T10:00:00Z")
+	require.NoError(t, err)
+
+	e := newExplorer("error")
+	e.receive(exploreResult{action: searchAction, page: explorePage(base, "/v1/logs?skipToken=older", Log{Message: "error"})})
+
+	// an older page is still loading when a new query is searched
+	require.Equal(t, olderAction, e.handleKey("up"))
+	stale := e.generation
+	e.handleKey("/")
+	e.handleKey("backspace")
+	require.Equal(t, searchAction, e.handleKey("enter"))
+
+	e.receive(exploreResult{action: olderAction, generation: stale, page: explorePage(base.Add(-time.Hour), "/v1/logs?skipToken=stale", Log{Message: "error older"})})
+	require.True(t, e.loading)
+	require.Equal(t, []string{"error"}, messages(e.logs))
+
+	e.receive(exploreResult{action: searchAction, generation: e.generation, page: explorePage(base, "/v1/logs?skipToken=next", Log{Message: "erro"})})
+	require.False(t, e.loading)
+	require.Equal(t, []string{"erro"}, messages(e.logs))
+	require.Equal(t, "/v1/logs?skipToken=next", e.nextPage)
+}
+
+func TestDraw(t *testing.T) {
+	var screen bytes.Buffer
+	require.NoError(t, draw(&screen, []string{"one", "two"}))
+	require.Equal(t, "\x1b[Hone\x1b[K\r\ntwo\x1b[K\x1b[J", screen.String())
+}
+
+func TestExploreQuery(t *testing.T) {
+	createConfigFile(t, configFile, "token: 1234567")
+
+	cmd := NewExploreCommand()
+	err := cmd.Init([]string{"--configfile", configFile, "-s", "www42", "--count", "20", "--max-time", "2000-01-01T11:00:00Z", "error"})
+	require.NoError(t, err)
+
+	base, err := time.Parse(time.RFC3339, "2000-01-01T10:00:00Z")
+	require.NoError(t, err)
+
+	e := newExplorer("timeout")
+	e.apply(searchAction, explorePage(base, "/v1/logs?skipToken=older", Log{Message: "timeout"}), nil)
+
+	q, err := cmd.exploreQuery(e, searchAction)
+	require.NoError(t, err)
+	require.Equal(t, "host:www42 timeout", q.Filter)
+	require.Equal(t, 20, q.PageSize)
+
+	q, err = cmd.exploreQuery(e, olderAction)
+	require.NoError(t, err)
+	require.Equal(t, "/v1/logs?skipToken=older", q.Cursor)
+
+	q, err = cmd.exploreQuery(e, newerAction)
+	require.NoError(t, err)
+	require.Equal(t, base, q.StartTime)
+	require.True(t, q.EndTime.IsZero())
+
+	err = cmd.Run(context.Background())
+	require.True(t, errors.Is(err, errNotTerminal), "error: %v", err)
+}
+
+func TestExploreFilters(t *testing.T) {
+	server, _ := newPagedServer(t, 1, 3)
+	createConfigFile(t, configFile, fmt.Sprintf("token: 1234567\napi-url: %s", server.URL))
+
+	cmd := NewExploreCommand()
+	require.NoError(t, cmd.Init([]string{"--configfile", configFile, "--grep-v", "log 1$"}))
+
+	results := make(chan exploreResult)
+	cmd.fetch(context.Background(), newExplorer(""), searchAction, results)
+
+	result := <-results
+	require.NoError(t, result.err)
+	require.Equal(t, []Log{{Message: "page 0 log 0"}, {Message: "page 0 log 2"}}, result.page.Logs)
+}
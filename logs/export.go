@@ -0,0 +1,503 @@
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	exportCommandName = "export"
+
+	ndjsonFormat = "ndjson"
+	csvFormat    = "csv"
+
+	defaultExportPageSize = 1000
+	defaultExportPrefix   = "swo-export"
+)
+
+var (
+	errExportFormat  = errors.New("unknown value of the format flag")
+	errExportSplit   = errors.New("--split-size and --split-hourly are mutually exclusive")
+	errSplitSizeFlag = errors.New("failed to parse --split-size flag")
+	errResumeQuery   = errors.New("the checkpoint belongs to an export with different flags or query")
+
+	csvHeader = []string{"time", "hostname", "program", "severity", "message"}
+)
+
+type exportCommand struct {
+	fs     *flag.FlagSet
+	help   *commandHelp
+	search *searcher
+	opts   *Options
+
+	dir        string
+	prefix     string
+	format     string
+	gzip       bool
+	splitSize  string
+	splitBytes int64
+	hourly     bool
+	resume     bool
+
+	// minTime and maxTime are the time range as given, before relative
+	// times are resolved
+	minTime string
+	maxTime string
+}
+
+func NewExportCommand() *exportCommand {
+	help := newCommandHelp(exportCommandName, "write all logs of a time range to files", "[--] [query]")
+	cmd := &exportCommand{
+		fs:   help.fs,
+		help: help,
+		opts: &Options{},
+	}
+
+	help.stringVar(&cmd.dir, "dir", ".", "DIR", "Directory to write the files to")
+	help.stringVar(&cmd.prefix, "prefix", defaultExportPrefix, "NAME", "Name prefix of the written files")
+	help.stringVar(&cmd.format, "format", ndjsonFormat, "[ndjson|csv]", "Format of the written files")
+	help.boolVar(&cmd.gzip, "gzip", "Compress the written files")
+	help.stringVar(&cmd.splitSize, "split-size", "", "SIZE", "Start a new file after SIZE uncompressed bytes, e.g. 100M").def = "off"
+	help.boolVar(&cmd.hourly, "split-hourly", "Write a file per hour of log time")
+	help.boolVar(&cmd.resume, "resume", "Continue an interrupted export")
+	help.intVar(&cmd.opts.count, "count", defaultExportPageSize, "NUMBER", "Number of log entries per request")
+	registerSearchFlags(help, cmd.opts, "export")
+	registerConnectionFlags(help, cmd.opts)
+
+	help.example(
+		"swo-cli export --min-time 'yesterday at 0:00' --max-time 'today at 0:00' --gzip --split-hourly",
+		"swo-cli export --min-time '2 days ago' --format csv --split-size 100M --dir incident-42",
+		"swo-cli export --min-time '2 days ago' --format csv --split-size 100M --dir incident-42 --resume",
+	)
+
+	return cmd
+}
+
+func (c *exportCommand) Init(args []string) error {
+	err := c.help.parse(args)
+	if err != nil {
+		return err
+	}
+
+	if c.format != ndjsonFormat && c.format != csvFormat {
+		return errExportFormat
+	}
+
+	if c.splitSize != "" {
+		if c.hourly {
+			return errExportSplit
+		}
+
+		c.splitBytes, err = parseSize(c.splitSize)
+		if err != nil {
+			return errors.Join(errSplitSizeFlag, err)
+		}
+	}
+
+	c.minTime, c.maxTime = c.opts.minTime, c.opts.maxTime
+	opts, err := c.opts.Init(c.fs.Args())
+	if err != nil {
+		return err
+	}
+
+	search, err := newSearcher(opts)
+	if err != nil {
+		return err
+	}
+
+	c.search = search
+
+	return nil
+}
+
+func (c *exportCommand) Run(ctx context.Context) error {
+	if c.search == nil {
+		return fmt.Errorf("%s command was not initialized", exportCommandName)
+	}
+
+	err := c.run(ctx)
+
+	return errors.Join(err, c.search.Close())
+}
+
+func (c *exportCommand) run(ctx context.Context) error {
+	if err := c.search.resolveGroup(ctx); err != nil {
+		return err
+	}
+
+	q, err := c.search.query()
+	if err != nil {
+		return fmt.Errorf("error while preparing http request to SWO: %w", err)
+	}
+
+	checkpointPath := filepath.Join(c.dir, c.prefix+".checkpoint.json")
+	query, err := c.queryHash(q)
+	if err != nil {
+		return err
+	}
+
+	cp := checkpoint{Query: query}
+	if c.resume {
+		cp, err = loadCheckpoint(checkpointPath)
+		if err != nil {
+			return err
+		}
+		if cp.Query != query {
+			return fmt.Errorf("%w, resume with the arguments of the interrupted export or remove %s", errResumeQuery, checkpointPath)
+		}
+
+		q.Cursor = cp.Cursor
+	}
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("error while creating export directory: %w", err)
+	}
+
+	w := &exportWriter{
+		dir:          c.dir,
+		prefix:       c.prefix,
+		format:       c.format,
+		gzip:         c.gzip,
+		splitBytes:   c.splitBytes,
+		hourly:       c.hourly,
+		part:         cp.Part,
+		resumeFile:   cp.File,
+		resumeOffset: cp.Offset,
+		resumeSize:   cp.Size,
+	}
+
+	if err := cp.save(checkpointPath); err != nil {
+		return errors.Join(err, w.Close())
+	}
+
+	for {
+		page, err := c.search.fetchPage(ctx, q)
+		if err != nil {
+			if ctx.Err() != nil {
+				err = fmt.Errorf("export interrupted after %d logs, run it again with --resume to continue: %w", cp.Count, err)
+			}
+
+			return errors.Join(err, w.Close())
+		}
+
+		for _, l := range page.Logs {
+			if !matchesFilters(c.opts.filters, l) {
+				continue
+			}
+
+			if err := w.write(l); err != nil {
+				return errors.Join(err, w.Close())
+			}
+
+			cp.Count++
+			cp.LastTime = l.Time
+		}
+
+		if page.NextPage == "" || len(page.Logs) == 0 {
+			break
+		}
+
+		cp.File, cp.Offset, err = w.sync()
+		if err != nil {
+			return errors.Join(err, w.Close())
+		}
+
+		q.Cursor = page.NextPage
+		cp.Cursor = page.NextPage
+		cp.Size = w.size
+		cp.Part = w.part
+		if err := cp.save(checkpointPath); err != nil {
+			return errors.Join(err, w.Close())
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	if err := removeCheckpoint(checkpointPath); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.search.output, "Exported %d logs to %d files in %s\n", cp.Count, len(w.files), c.dir)
+	return err
+}
+
+// queryHash identifies the logs an export writes and how, so that --resume
+// never continues a checkpoint into the files of a different export. The
+// time range is taken as given, as relative times resolve differently on
+// every run.
+func (c *exportCommand) queryHash(q Query) (string, error) {
+	content, err := json.Marshal(struct {
+		Filter     string   `json:"filter"`
+		Group      string   `json:"group"`
+		MinTime    string   `json:"minTime"`
+		MaxTime    string   `json:"maxTime"`
+		Grep       string   `json:"grep"`
+		GrepV      string   `json:"grepV"`
+		Match      []string `json:"match"`
+		Format     string   `json:"format"`
+		Gzip       bool     `json:"gzip"`
+		SplitBytes int64    `json:"splitBytes"`
+		Hourly     bool     `json:"hourly"`
+	}{
+		Filter:     q.Filter,
+		Group:      q.Group,
+		MinTime:    c.minTime,
+		MaxTime:    c.maxTime,
+		Grep:       c.opts.grep,
+		GrepV:      c.opts.grepV,
+		Match:      c.opts.match,
+		Format:     c.format,
+		Gzip:       c.gzip,
+		SplitBytes: c.splitBytes,
+		Hourly:     c.hourly,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (c *exportCommand) Name() string {
+	return exportCommandName
+}
+
+func (c *exportCommand) Usage() {
+	c.fs.Usage()
+}
+
+// exportWriter writes logs to a file per hour, a new numbered file every
+// splitBytes, or a single file.
+type exportWriter struct {
+	dir        string
+	prefix     string
+	format     string
+	gzip       bool
+	splitBytes int64
+	hourly     bool
+
+	// resumeFile is truncated to resumeOffset and appended to instead of
+	// being replaced when it is opened. resumeSize is its uncompressed size
+	// at that offset.
+	resumeFile   string
+	resumeOffset int64
+	resumeSize   int64
+
+	part int
+	key  string
+	// size counts the uncompressed bytes of the current file, so that
+	// splitBytes means the same with and without gzip.
+	size  int64
+	files []string
+
+	file *os.File
+	gz   *gzip.Writer
+	buf  *bufio.Writer
+}
+
+func (w *exportWriter) write(l Log) error {
+	key := ""
+	if w.hourly {
+		key = l.Time.UTC().Format("2006-01-02T15")
+	}
+
+	if w.file == nil || key != w.key || w.full() {
+		resuming := w.resumeFile != ""
+		if err := w.open(key); err != nil {
+			return err
+		}
+
+		// the part a resumed export was writing may be full already
+		if resuming && w.resumeFile == "" && w.full() {
+			if err := w.open(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	line, err := w.encode(l)
+	if err != nil {
+		return err
+	}
+
+	n, err := w.buf.Write(line)
+	w.size += int64(n)
+
+	return err
+}
+
+func (w *exportWriter) full() bool {
+	return w.splitBytes > 0 && w.size >= w.splitBytes
+}
+
+func (w *exportWriter) encode(l Log) ([]byte, error) {
+	if w.format == csvFormat {
+		return encodeCSV([]string{l.Time.Format(time.RFC3339Nano), l.Hostname, l.Program, l.Severity, l.Message})
+	}
+
+	line, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(line, '\n'), nil
+}
+
+func encodeCSV(record []string) ([]byte, error) {
+	var b bytes.Buffer
+	writer := csv.NewWriter(&b)
+	if err := writer.Write(record); err != nil {
+		return nil, err
+	}
+
+	writer.Flush()
+	return b.Bytes(), writer.Error()
+}
+
+func (w *exportWriter) filename(key string) string {
+	name := w.prefix
+	switch {
+	case w.hourly:
+		name = fmt.Sprintf("%s-%s", name, key)
+	case w.splitBytes > 0:
+		name = fmt.Sprintf("%s-%04d", name, w.part)
+	}
+
+	name = name + "." + w.format
+	if w.gzip {
+		name = name + ".gz"
+	}
+
+	return filepath.Join(w.dir, name)
+}
+
+func (w *exportWriter) open(key string) error {
+	// a resumed export continues the part it was writing
+	if w.splitBytes > 0 && (w.file != nil || w.part == 0) {
+		w.part++
+	}
+
+	if err := w.closeFile(); err != nil {
+		return err
+	}
+
+	path := w.filename(key)
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	size := int64(0)
+	if path == w.resumeFile {
+		if err := os.Truncate(path, w.resumeOffset); err != nil {
+			return fmt.Errorf("error while truncating export file to the checkpoint: %w", err)
+		}
+
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		size = w.resumeSize
+		w.resumeFile = ""
+	}
+
+	file, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return fmt.Errorf("error while opening export file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return errors.Join(err, file.Close())
+	}
+
+	var out io.Writer = file
+	w.gz = nil
+	if w.gzip {
+		// appending starts a new gzip member, which readers concatenate
+		w.gz = gzip.NewWriter(file)
+		out = w.gz
+	}
+
+	w.file = file
+	w.buf = bufio.NewWriter(out)
+	w.key = key
+	w.size = size
+	w.files = append(w.files, path)
+
+	if w.format == csvFormat && info.Size() == 0 {
+		header, err := encodeCSV(csvHeader)
+		if err != nil {
+			return err
+		}
+
+		n, err := w.buf.Write(header)
+		w.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sync writes out everything buffered and returns the current file with its
+// size, which is where a resumed export continues. A gzip member is finished
+// so that the file is a valid gzip stream up to that size.
+func (w *exportWriter) sync() (string, int64, error) {
+	if w.file == nil {
+		return "", 0, nil
+	}
+
+	if err := w.buf.Flush(); err != nil {
+		return "", 0, err
+	}
+
+	if w.gz != nil {
+		if err := w.gz.Close(); err != nil {
+			return "", 0, err
+		}
+
+		w.gz.Reset(w.file)
+	}
+
+	info, err := w.file.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+
+	return w.file.Name(), info.Size(), nil
+}
+
+func (w *exportWriter) closeFile() error {
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.buf.Flush()
+	if w.gz != nil {
+		err = errors.Join(err, w.gz.Close())
+	}
+
+	err = errors.Join(err, w.file.Close())
+	w.file = nil
+
+	return err
+}
+
+func (w *exportWriter) Close() error {
+	return w.closeFile()
+}
+
+func (c *exportCommand) Help() *commandHelp {
+	return c.help
+}
@@ -0,0 +1,326 @@
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func initExportCommand(t *testing.T, apiUrl string, flags ...string) *exportCommand {
+	createConfigFile(t, configFile, fmt.Sprintf("token: 1234567\napi-url: %s", apiUrl))
+
+	cmd := NewExportCommand()
+	err := cmd.Init(append([]string{"--configfile", configFile}, flags...))
+	require.NoError(t, err)
+
+	cmd.search.output = &bytes.Buffer{}
+
+	return cmd
+}
+
+func readLines(t *testing.T, path string) []string {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+
+	return lines
+}
+
+func TestExportNDJSON(t *testing.T) {
+	server, _ := newPagedServer(t, 3, 2)
+	dir := t.TempDir()
+
+	cmd := initExportCommand(t, server.URL, "--dir", dir, "--grep", "log 0")
+	require.NoError(t, cmd.Run(context.Background()))
+
+	lines := readLines(t, filepath.Join(dir, "swo-export.ndjson"))
+	require.Len(t, lines, 3)
+
+	var l Log
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &l))
+	require.Equal(t, "page 2 log 0", l.Message)
+
+	_, err := os.Stat(filepath.Join(dir, "swo-export.checkpoint.json"))
+	require.True(t, errors.Is(err, os.ErrNotExist), "error: %v", err)
+	require.Equal(t, fmt.Sprintf("Exported 3 logs to 1 files in %s\n", dir), cmd.search.output.(*bytes.Buffer).String())
+}
+
+func TestExportCSVSplitSize(t *testing.T) {
+	server, _ := newPagedServer(t, 2, 2)
+	dir := t.TempDir()
+
+	cmd := initExportCommand(t, server.URL, "--dir", dir, "--format", "csv", "--split-size", "1")
+	require.NoError(t, cmd.Run(context.Background()))
+
+	for i := 1; i <= 4; i++ {
+		lines := readLines(t, filepath.Join(dir, fmt.Sprintf("swo-export-%04d.csv", i)))
+		require.Len(t, lines, 2)
+		require.Equal(t, "time,hostname,program,severity,message", lines[0])
+	}
+}
+
+func TestExportGzip(t *testing.T) {
+	server, _ := newPagedServer(t, 2, 2)
+	dir := t.TempDir()
+
+	cmd := initExportCommand(t, server.URL, "--dir", dir, "--gzip", "--prefix", "incident")
+	require.NoError(t, cmd.Run(context.Background()))
+
+	file, err := os.Open(filepath.Join(dir, "incident.ndjson.gz"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	var count int
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		count++
+	}
+	require.NoError(t, scanner.Err())
+	require.Equal(t, 4, count)
+}
+
+func TestExportResume(t *testing.T) {
+	server, requests := newPagedServer(t, 3, 2)
+	dir := t.TempDir()
+
+	cmd := initExportCommand(t, server.URL, "--dir", dir, "--resume")
+	err := cmd.Run(context.Background())
+	require.True(t, errors.Is(err, errNothingToResume), "error: %v", err)
+
+	// the first two pages were exported, the third was partially written when the export was interrupted
+	path := filepath.Join(dir, "swo-export.ndjson")
+	var content bytes.Buffer
+	for _, message := range []string{"page 0 log 0", "page 0 log 1", "page 1 log 0", "page 1 log 1"} {
+		line, err := json.Marshal(Log{Message: message})
+		require.NoError(t, err)
+		content.Write(append(line, '\n'))
+	}
+	offset := int64(content.Len())
+	content.WriteString(`{"message":"page 2 lo`)
+	require.NoError(t, os.WriteFile(path, content.Bytes(), 0o644))
+
+	cmd = initExportCommand(t, server.URL, "--dir", dir, "--resume")
+	q, err := cmd.search.query()
+	require.NoError(t, err)
+	query, err := cmd.queryHash(q)
+	require.NoError(t, err)
+
+	cp := checkpoint{Query: query, Cursor: "/v1/logs?page=2", Count: 4, File: path, Offset: offset}
+	require.NoError(t, cp.save(filepath.Join(dir, "swo-export.checkpoint.json")))
+
+	require.NoError(t, cmd.Run(context.Background()))
+	require.Equal(t, 1, *requests)
+
+	lines := readLines(t, path)
+	require.Len(t, lines, 6)
+	for i, line := range lines {
+		var l Log
+		require.NoError(t, json.Unmarshal([]byte(line), &l))
+		require.Equal(t, fmt.Sprintf("page %d log %d", i/2, i%2), l.Message)
+	}
+	require.Equal(t, fmt.Sprintf("Exported 6 logs to 1 files in %s\n", dir), cmd.search.output.(*bytes.Buffer).String())
+}
+
+func TestExportResumeQueryMismatch(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		require.NoError(t, json.NewEncoder(w).Encode(LogsData{Logs: []Log{{Message: "page 0 log 0"}}, PageInfo: PageInfo{NextPage: "/v1/logs?page=1"}}))
+	}))
+	t.Cleanup(server.Close)
+	dir := t.TempDir()
+
+	cmd := initExportCommand(t, server.URL, "--dir", dir, "--min-time", "1 day ago", "error")
+	require.Error(t, cmd.Run(context.Background()))
+
+	for _, flags := range [][]string{
+		{"warning"},
+		{"--format", "csv", "error"},
+		{"--gzip", "error"},
+		{"--split-hourly", "error"},
+		{"--grep", "timeout", "error"},
+		{"--max-time", "1 hour ago", "error"},
+	} {
+		cmd = initExportCommand(t, server.URL, append([]string{"--dir", dir, "--min-time", "1 day ago", "--resume"}, flags...)...)
+		err := cmd.Run(context.Background())
+		require.True(t, errors.Is(err, errResumeQuery), "%v: %v", flags, err)
+	}
+	require.Equal(t, 2, requests)
+
+	// relative times are compared as given, not resolved
+	cmd = initExportCommand(t, server.URL, "--dir", dir, "--min-time", "1 day ago", "--resume", "error")
+	err := cmd.Run(context.Background())
+	require.False(t, errors.Is(err, errResumeQuery), "error: %v", err)
+	require.Equal(t, 3, requests)
+}
+
+func TestExportResumeGzipSplit(t *testing.T) {
+	server, _ := newPagedServer(t, 3, 2)
+	dir := t.TempDir()
+
+	cmd := initExportCommand(t, server.URL, "--dir", dir, "--gzip", "--split-size", "1M")
+	require.NoError(t, cmd.Run(context.Background()))
+	full, err := os.ReadFile(filepath.Join(dir, "swo-export-0001.ndjson.gz"))
+	require.NoError(t, err)
+
+	// interrupt a second export after its first page by failing the second request
+	var requests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/logs", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		server.Config.Handler.ServeHTTP(w, r)
+	})
+	failing := httptest.NewServer(mux)
+	t.Cleanup(failing.Close)
+
+	cmd = initExportCommand(t, failing.URL, "--dir", dir, "--gzip", "--split-size", "1M")
+	require.Error(t, cmd.Run(context.Background()))
+
+	cmd = initExportCommand(t, failing.URL, "--dir", dir, "--gzip", "--split-size", "1M", "--resume")
+	require.NoError(t, cmd.Run(context.Background()))
+
+	_, err = os.Stat(filepath.Join(dir, "swo-export-0002.ndjson.gz"))
+	require.True(t, errors.Is(err, os.ErrNotExist), "error: %v", err)
+
+	resumed, err := os.ReadFile(filepath.Join(dir, "swo-export-0001.ndjson.gz"))
+	require.NoError(t, err)
+	require.Equal(t, gunzip(t, full), gunzip(t, resumed))
+}
+
+// exportFiles returns the decompressed content of the files in dir by name,
+// without the checkpoint.
+func exportFiles(t *testing.T, dir string) map[string]string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, entry := range entries {
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		require.NoError(t, err)
+		files[entry.Name()] = gunzip(t, content)
+	}
+
+	return files
+}
+
+func TestExportResumeSplitPoints(t *testing.T) {
+	server, _ := newPagedServer(t, 3, 2)
+	split := []string{"--gzip", "--split-size", "150"}
+
+	dir := t.TempDir()
+	cmd := initExportCommand(t, server.URL, append([]string{"--dir", dir}, split...)...)
+	require.NoError(t, cmd.Run(context.Background()))
+	full := exportFiles(t, dir)
+	require.Len(t, full, 3)
+
+	// interrupt a second export after its first page by failing the second request
+	var requests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/logs", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		server.Config.Handler.ServeHTTP(w, r)
+	})
+	failing := httptest.NewServer(mux)
+	t.Cleanup(failing.Close)
+
+	dir = t.TempDir()
+	cmd = initExportCommand(t, failing.URL, append([]string{"--dir", dir}, split...)...)
+	require.Error(t, cmd.Run(context.Background()))
+
+	cmd = initExportCommand(t, failing.URL, append([]string{"--dir", dir, "--resume"}, split...)...)
+	require.NoError(t, cmd.Run(context.Background()))
+	require.Equal(t, full, exportFiles(t, dir))
+}
+
+func TestExportSplitHourly(t *testing.T) {
+	base := time.Date(2000, 1, 1, 10, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data LogsData
+		for _, minutes := range []int{130, 75, 61, 30} {
+			data.Logs = append(data.Logs, Log{Time: base.Add(time.Duration(minutes) * time.Minute), Message: strconv.Itoa(minutes)})
+		}
+
+		require.NoError(t, json.NewEncoder(w).Encode(data))
+	}))
+	t.Cleanup(server.Close)
+	dir := t.TempDir()
+
+	cmd := initExportCommand(t, server.URL, "--dir", dir, "--split-hourly")
+	require.NoError(t, cmd.Run(context.Background()))
+
+	for name, expected := range map[string][]string{
+		"swo-export-2000-01-01T12.ndjson": {"130"},
+		"swo-export-2000-01-01T11.ndjson": {"75", "61"},
+		"swo-export-2000-01-01T10.ndjson": {"30"},
+	} {
+		var messages []string
+		for _, line := range readLines(t, filepath.Join(dir, name)) {
+			var l Log
+			require.NoError(t, json.Unmarshal([]byte(line), &l))
+			messages = append(messages, l.Message)
+		}
+		require.Equal(t, expected, messages, name)
+	}
+	require.Equal(t, fmt.Sprintf("Exported 4 logs to 3 files in %s\n", dir), cmd.search.output.(*bytes.Buffer).String())
+}
+
+func gunzip(t *testing.T, content []byte) string {
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	require.NoError(t, err)
+
+	decompressed, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	return string(decompressed)
+}
+
+func TestExportFlags(t *testing.T) {
+	createConfigFile(t, configFile, "token: 1234567")
+
+	cmd := NewExportCommand()
+	err := cmd.Init([]string{"--configfile", configFile, "--format", "xml"})
+	require.True(t, errors.Is(err, errExportFormat), "error: %v", err)
+
+	cmd = NewExportCommand()
+	err = cmd.Init([]string{"--configfile", configFile, "--split-size", "1M", "--split-hourly"})
+	require.True(t, errors.Is(err, errExportSplit), "error: %v", err)
+}
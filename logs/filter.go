@@ -0,0 +1,95 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	errGrepFlag  = errors.New("failed to parse --grep flag")
+	errMatchFlag = errors.New("failed to parse --match flag")
+
+	logFields = map[string]func(Log) string{
+		"message":  func(l Log) string { return l.Message },
+		"hostname": func(l Log) string { return l.Hostname },
+		"host":     func(l Log) string { return l.Hostname },
+		"program":  func(l Log) string { return l.Program },
+		"severity": func(l Log) string { return l.Severity },
+	}
+)
+
+// matchFlag collects repeated --match field=regex flags.
+type matchFlag []string
+
+func (m *matchFlag) String() string {
+	return strings.Join(*m, ",")
+}
+
+func (m *matchFlag) Set(value string) error {
+	*m = append(*m, value)
+	return nil
+}
+
+type logFilter struct {
+	field  func(Log) string
+	re     *regexp.Regexp
+	invert bool
+}
+
+func (f logFilter) match(l Log) bool {
+	return f.re.MatchString(f.field(l)) != f.invert
+}
+
+// newLogFilters compiles --grep, --grep-v and --match flags into filters
+// applied to every fetched log before printing.
+func newLogFilters(grep, grepV string, matches []string) ([]logFilter, error) {
+	var filters []logFilter
+	for _, pattern := range []struct {
+		value  string
+		invert bool
+	}{{grep, false}, {grepV, true}} {
+		if pattern.value == "" {
+			continue
+		}
+
+		re, err := regexp.Compile(pattern.value)
+		if err != nil {
+			return nil, errors.Join(errGrepFlag, err)
+		}
+
+		filters = append(filters, logFilter{field: logFields["message"], re: re, invert: pattern.invert})
+	}
+
+	for _, m := range matches {
+		name, pattern, ok := strings.Cut(m, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: expected field=regex, got %q", errMatchFlag, m)
+		}
+
+		field, ok := logFields[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", errMatchFlag, name)
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Join(errMatchFlag, err)
+		}
+
+		filters = append(filters, logFilter{field: field, re: re})
+	}
+
+	return filters, nil
+}
+
+func matchesFilters(filters []logFilter, l Log) bool {
+	for _, f := range filters {
+		if !f.match(l) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,121 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogFilters(t *testing.T) {
+	l := Log{Message: "connection timeout after 30ms", Hostname: "www42", Program: "nginx", Severity: "ERROR"}
+
+	testCases := []struct {
+		name          string
+		grep          string
+		grepV         string
+		matches       []string
+		expected      bool
+		expectedError error
+	}{
+		{
+			name:     "no filters",
+			expected: true,
+		},
+		{
+			name:     "grep matches",
+			grep:     `timeout after \d+ms`,
+			expected: true,
+		},
+		{
+			name:     "grep-v excludes",
+			grepV:    "timeout",
+			expected: false,
+		},
+		{
+			name:     "match on fields",
+			matches:  []string{"program=^ngi", "host=42$"},
+			expected: true,
+		},
+		{
+			name:     "match on field fails",
+			matches:  []string{"severity=INFO"},
+			expected: false,
+		},
+		{
+			name:          "invalid grep",
+			grep:          "(",
+			expectedError: errGrepFlag,
+		},
+		{
+			name:          "unknown field",
+			matches:       []string{"color=red"},
+			expectedError: errMatchFlag,
+		},
+		{
+			name:          "missing separator",
+			matches:       []string{"program"},
+			expectedError: errMatchFlag,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filters, err := newLogFilters(tc.grep, tc.grepV, tc.matches)
+			require.True(t, errors.Is(err, tc.expectedError), "error: %v, expected: %v", err, tc.expectedError)
+			if tc.expectedError != nil {
+				return
+			}
+
+			require.Equal(t, tc.expected, matchesFilters(filters, l))
+		})
+	}
+}
+
+func TestFetchFilteredPaging(t *testing.T) {
+	pages := map[string]LogsData{
+		"": {
+			Logs:     []Log{{Message: "error one"}, {Message: "info one"}},
+			PageInfo: PageInfo{NextPage: "/v1/logs?skipToken=2"},
+		},
+		"2": {
+			Logs:     []Log{{Message: "info two"}, {Message: "error two"}, {Message: "error three"}},
+			PageInfo: PageInfo{NextPage: "/v1/logs?skipToken=3"},
+		},
+		"3": {
+			Logs: []Log{{Message: "error four"}},
+		},
+	}
+
+	var requests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/logs", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		data, err := json.Marshal(pages[r.URL.Query().Get("skipToken")])
+		require.NoError(t, err)
+
+		_, err = w.Write(data)
+		require.NoError(t, err)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	createConfigFile(t, configFile, fmt.Sprintf("token: 1234567\napi-url: %s", server.URL))
+
+	cmd := NewLogsCommand()
+	err := cmd.Init([]string{"--configfile", configFile, "--count", "2", "--grep", "^error"})
+	require.NoError(t, err)
+
+	q, err := cmd.search.query()
+	require.NoError(t, err)
+
+	logs, err := cmd.search.fetchFiltered(context.Background(), q)
+	require.NoError(t, err)
+	require.Equal(t, []Log{{Message: "error one"}, {Message: "error two"}}, logs.Logs)
+	require.Equal(t, 2, requests)
+}
@@ -0,0 +1,135 @@
+package logs
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var errAttachedValue = errors.New("ambiguous flag after the query")
+
+// parse parses args GNU style before handing them to the flag package:
+//
+//   - flags may follow the query terms, e.g. `error --count 5`
+//   - short flags may be combined, e.g. -jg GROUP_ID, and a short flag
+//     given first may carry its value, e.g. -A3 or -g=GROUP_ID
+//   - long flags take their value as --color=all or --color all
+//   - everything after -- is a query term, e.g. `-- -redis`
+//
+// Once a query term was seen, arguments that are not flags of the command,
+// such as the negation in `nginx -accepted`, are query terms as well. Only
+// numbers may be attached to short flags there, e.g. `panic -C3`; anything
+// else, e.g. `error -gateway`, could be either and is rejected.
+func (h *commandHelp) parse(args []string) error {
+	var flags, terms []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			terms = append(terms, args[i+1:]...)
+			break
+		}
+
+		if arg == "-" || !strings.HasPrefix(arg, "-") {
+			terms = append(terms, arg)
+			continue
+		}
+
+		expanded, needsValue, ok, err := h.expand(arg, len(terms) == 0)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if len(terms) > 0 {
+				terms = append(terms, arg)
+				continue
+			}
+
+			// let the flag package report the unknown flag
+			expanded = []string{arg}
+		}
+
+		flags = append(flags, expanded...)
+		if needsValue && i+1 < len(args) {
+			i++
+			flags = append(flags, args[i])
+		}
+	}
+
+	if err := h.fs.Parse(flags); err != nil {
+		return err
+	}
+	if err := h.applyOutputFormat(); err != nil {
+		return err
+	}
+
+	// parsing again only sets the query terms as the remaining arguments
+	return h.fs.Parse(append([]string{"--"}, terms...))
+}
+
+// expand splits a flag argument into arguments the flag package
+// understands and reports whether the next argument is its value. It
+// returns false when a single dash arg is not made of flags of the command.
+// attach allows any value attached to a short flag, e.g. -gID, otherwise
+// only a number for a numeric flag, e.g. -A3.
+func (h *commandHelp) expand(arg string, attach bool) ([]string, bool, bool, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+	name, _, hasValue := strings.Cut(trimmed, "=")
+	if name == "h" || name == "help" {
+		return []string{arg}, false, true, nil
+	}
+
+	if f := h.fs.Lookup(name); f != nil {
+		return []string{arg}, !hasValue && !isBoolFlag(f), true, nil
+	}
+
+	if strings.HasPrefix(arg, "--") {
+		// unknown long flags are reported by the flag package
+		return []string{arg}, false, true, nil
+	}
+
+	var expanded []string
+	for i, r := range trimmed {
+		f := h.fs.Lookup(string(r))
+		if f == nil {
+			return nil, false, false, nil
+		}
+
+		expanded = append(expanded, "-"+string(r))
+		if isBoolFlag(f) {
+			continue
+		}
+
+		value := strings.TrimPrefix(trimmed[i+utf8.RuneLen(r):], "=")
+		if value == "" {
+			return expanded, true, true, nil
+		}
+		if !attach && !isIntValue(f, value) {
+			return nil, false, false, fmt.Errorf("%w: %s, give the value as -%c %s or search for it after --", errAttachedValue, arg, r, value)
+		}
+
+		return append(expanded, value), false, true, nil
+	}
+
+	return expanded, false, true, nil
+}
+
+func isIntValue(f *flag.Flag, value string) bool {
+	getter, ok := f.Value.(flag.Getter)
+	if !ok {
+		return false
+	}
+	if _, ok := getter.Get().(int); !ok {
+		return false
+	}
+
+	_, err := strconv.Atoi(value)
+	return err == nil
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag()
+}
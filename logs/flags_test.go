@@ -0,0 +1,144 @@
+package logs
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFlags(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		json          bool
+		group         string
+		color         string
+		after         int
+		terms         []string
+		expectedError string
+	}{
+		{
+			name:  "go style",
+			args:  []string{"-json", "-group", "id", "-color=all", "error"},
+			json:  true,
+			group: "id",
+			color: "all",
+			terms: []string{"error"},
+		},
+		{
+			name:  "combined short flags",
+			args:  []string{"-jg", "id", "error"},
+			json:  true,
+			group: "id",
+			terms: []string{"error"},
+		},
+		{
+			name:  "attached short values",
+			args:  []string{"-A3", "-g=id"},
+			group: "id",
+			after: 3,
+			terms: []string{},
+		},
+		{
+			name:  "long flag values",
+			args:  []string{"--color=all", "--group", "-id"},
+			group: "-id",
+			color: "all",
+			terms: []string{},
+		},
+		{
+			name:  "interspersed flags",
+			args:  []string{"connection", "--color", "program", "refused", "-j"},
+			json:  true,
+			color: "program",
+			terms: []string{"connection", "refused"},
+		},
+		{
+			name:  "negations after query terms",
+			args:  []string{"nginx", "-accepted", "-jx", "-", "-A", "1"},
+			after: 1,
+			terms: []string{"nginx", "-accepted", "-jx", "-"},
+		},
+		{
+			name:  "attached number after query terms",
+			args:  []string{"error", "-A3", "-jA=2"},
+			json:  true,
+			after: 2,
+			terms: []string{"error"},
+		},
+		{
+			name:          "attached value after query terms",
+			args:          []string{"error", "-gID"},
+			expectedError: "ambiguous flag after the query: -gID, give the value as -g ID or search for it after --",
+		},
+		{
+			name:          "attached text to numeric flag after query terms",
+			args:          []string{"error", "-Apache"},
+			expectedError: "ambiguous flag after the query: -Apache, give the value as -A pache or search for it after --",
+		},
+		{
+			name:  "double dash",
+			args:  []string{"-j", "--", "-redis", "--color", "all"},
+			json:  true,
+			terms: []string{"-redis", "--color", "all"},
+		},
+		{
+			name:          "unknown short flag",
+			args:          []string{"-redis"},
+			expectedError: "flag provided but not defined: -redis",
+		},
+		{
+			name:          "unknown long flag after query terms",
+			args:          []string{"error", "--colour", "all"},
+			expectedError: "flag provided but not defined: -colour",
+		},
+		{
+			name:          "missing value",
+			args:          []string{"error", "-jg"},
+			expectedError: "flag needs an argument: -g",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newCommandHelp("test", "test command", "[query]")
+			h.fs.SetOutput(io.Discard)
+			h.fs.Usage = func() {}
+
+			var json bool
+			var group, color string
+			var after int
+			h.boolVar(&json, "j,json", "Output JSON")
+			h.stringVar(&group, "g,group", "", "GROUP_ID", "Group ID to search")
+			h.stringVar(&color, "color", "", "COLOR", "Color")
+			h.intVar(&after, "A", 0, "NUMBER", "Logs after each match")
+
+			err := h.parse(tc.args)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.json, json)
+			require.Equal(t, tc.group, group)
+			require.Equal(t, tc.color, color)
+			require.Equal(t, tc.after, after)
+			require.Equal(t, tc.terms, h.fs.Args())
+		})
+	}
+}
+
+func TestParseFlagsHelp(t *testing.T) {
+	for _, args := range [][]string{{"-h"}, {"--help"}, {"error", "-h"}} {
+		h := newCommandHelp("test", "test command", "[query]")
+		h.fs.SetOutput(io.Discard)
+		h.fs.Usage = func() {}
+
+		err := h.parse(args)
+		require.True(t, errors.Is(err, flag.ErrHelp), "%v error: %v", args, err)
+	}
+}
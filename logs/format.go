@@ -0,0 +1,90 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+)
+
+const formatCommandName = "format"
+
+var errFormatArgs = errors.New("format accepts a single file or glob pattern")
+
+type formatCommand struct {
+	fs     *flag.FlagSet
+	help   *commandHelp
+	search *searcher
+	opts   *Options
+}
+
+func NewFormatCommand() *formatCommand {
+	help := newCommandHelp(formatCommandName, "render saved --json or exported logs as text", "[file]")
+	cmd := &formatCommand{
+		fs:   help.fs,
+		help: help,
+		opts: &Options{},
+	}
+
+	help.stringVar(&cmd.opts.color, "color", "", "[program|system|all|off]", "Color the program, the system, both or nothing")
+	registerOutputFlags(help, cmd.opts)
+
+	help.example(
+		"swo-cli logs --json --min-time '1 hour ago' > saved.json; swo-cli format --color all < saved.json",
+		"swo-cli format 'incident-42/swo-export-*.ndjson.gz' | less -R",
+	)
+
+	return cmd
+}
+
+func (c *formatCommand) Init(args []string) error {
+	err := c.help.parse(args)
+	if err != nil {
+		return err
+	}
+
+	switch c.fs.NArg() {
+	case 0:
+		c.opts.fromFile = "-"
+	case 1:
+		c.opts.fromFile = c.fs.Arg(0)
+	default:
+		return errFormatArgs
+	}
+
+	opts, err := c.opts.Init(nil)
+	if err != nil {
+		return err
+	}
+
+	search, err := newSearcher(opts)
+	if err != nil {
+		return err
+	}
+
+	c.search = search
+
+	return nil
+}
+
+func (c *formatCommand) Run(ctx context.Context) error {
+	if c.search == nil {
+		return fmt.Errorf("%s command was not initialized", formatCommandName)
+	}
+
+	err := c.search.printResult(&LogsData{Logs: c.search.offline.logs})
+
+	return errors.Join(err, c.search.Close())
+}
+
+func (c *formatCommand) Name() string {
+	return formatCommandName
+}
+
+func (c *formatCommand) Usage() {
+	c.fs.Usage()
+}
+
+func (c *formatCommand) Help() *commandHelp {
+	return c.help
+}
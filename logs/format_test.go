@@ -0,0 +1,64 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	base, err := time.Parse(time.RFC3339, "2000-01-01T10:00:00Z")
+	require.NoError(t, err)
+
+	saved, err := json.Marshal(LogsData{Logs: []Log{
+		{Time: base.Add(time.Minute), Message: "messageTwo", Hostname: "hostnameTwo", Program: "programTwo"},
+		{Time: base, Message: "messageOne", Hostname: "hostnameOne", Program: "programOne"},
+	}})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "saved.json")
+	require.NoError(t, os.WriteFile(path, saved, 0o644))
+
+	expected := "Jan 01 10:00:00 hostnameOne programOne messageOne\nJan 01 10:01:00 hostnameTwo programTwo messageTwo\n"
+
+	cmd := NewFormatCommand()
+	require.NoError(t, cmd.Init([]string{path}))
+
+	var output bytes.Buffer
+	cmd.search.output = &output
+	require.NoError(t, cmd.Run(context.Background()))
+	require.Equal(t, expected, output.String())
+
+	stdin, err := os.Open(path)
+	require.NoError(t, err)
+	defer stdin.Close()
+
+	original := os.Stdin
+	os.Stdin = stdin
+	t.Cleanup(func() {
+		os.Stdin = original
+	})
+
+	cmd = NewFormatCommand()
+	require.NoError(t, cmd.Init([]string{"--color", "off"}))
+
+	output.Reset()
+	cmd.search.output = &output
+	require.NoError(t, cmd.Run(context.Background()))
+	require.Equal(t, expected, output.String())
+}
+
+func TestFormatInit(t *testing.T) {
+	err := NewFormatCommand().Init([]string{"one.json", "two.json"})
+	require.True(t, errors.Is(err, errFormatArgs), "error: %v", err)
+
+	err = NewFormatCommand().Init([]string{"--color", "rainbow"})
+	require.True(t, errors.Is(err, errColorFlag), "error: %v", err)
+}
@@ -0,0 +1,284 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const groupsCommandName = "groups"
+
+var (
+	errGroupsArgs   = errors.New("groups takes no arguments")
+	errUnknownGroup = errors.New("unknown group")
+)
+
+type LogGroup struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type GroupsData struct {
+	Groups   []LogGroup `json:"groups"`
+	PageInfo `json:"pageInfo"`
+}
+
+// Groups fetches one page of the log groups. The next page is requested by
+// passing GroupsData.NextPage as cursor.
+func (c *Client) Groups(ctx context.Context, cursor string) (*GroupsData, error) {
+	request, err := c.prepareGroupsRequest(ctx, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("error while preparing http request to SWO: %w", err)
+	}
+
+	var groups GroupsData
+	if err := c.get(request, &groups); err != nil {
+		return nil, err
+	}
+
+	return &groups, nil
+}
+
+func (c *Client) prepareGroupsRequest(ctx context.Context, cursor string) (*http.Request, error) {
+	if cursor != "" {
+		return c.prepareNextPageRequest(ctx, cursor)
+	}
+
+	endpoint, err := url.JoinPath(c.apiUrl, "v1/logs/groups")
+	if err != nil {
+		return nil, err
+	}
+
+	return c.newRequest(ctx, endpoint)
+}
+
+// groupIDsFile returns the cache file of the IDs of log groups by name, for
+// resolving --group names and completing them.
+func groupIDsFile(apiUrl, token string) string {
+	return "group-ids-" + accountKey(apiUrl, token)
+}
+
+// resolveGroup replaces the --group name with its cached ID. Groups are
+// only listed for a value that cannot be an ID, e.g. "web servers";
+// anything else that is not cached is taken as an ID, and `swo-cli groups`
+// refreshes the cached names.
+func (s *searcher) resolveGroup(ctx context.Context) error {
+	group := s.opts.group
+	if group == "" || s.opts.fromFile != "" {
+		return nil
+	}
+
+	ids := cachedGroupIDs(s.opts.ApiUrl, s.opts.Token)
+	if id, ok := ids[group]; ok {
+		s.opts.group = id
+		return nil
+	}
+	if maybeGroupID(group) {
+		return nil
+	}
+
+	groups, err := listGroups(ctx, s.client, s.limiter)
+	if err != nil {
+		return fmt.Errorf("error while listing groups to resolve %q: %w", group, err)
+	}
+
+	ids = cacheGroupIDs(s.opts.ApiUrl, s.opts.Token, groups)
+	id, ok := ids[group]
+	if !ok {
+		return fmt.Errorf("%w %q, see swo-cli groups", errUnknownGroup, group)
+	}
+
+	s.opts.group = id
+	return nil
+}
+
+// maybeGroupID reports whether group is made of the letters, digits,
+// dashes and underscores of an ID.
+func maybeGroupID(group string) bool {
+	for _, r := range group {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cachedGroupIDs returns the IDs of the groups of the account listed last,
+// by name.
+func cachedGroupIDs(apiUrl, token string) map[string]string {
+	var ids map[string]string
+	if err := readCacheFile(groupIDsFile(apiUrl, token), &ids); err != nil {
+		return nil
+	}
+
+	return ids
+}
+
+// cacheGroupIDs replaces the cached IDs of the groups of the account.
+func cacheGroupIDs(apiUrl, token string, groups []LogGroup) map[string]string {
+	ids := map[string]string{}
+	for _, group := range groups {
+		ids[group.Name] = group.ID
+	}
+	if err := writeCacheFile(groupIDsFile(apiUrl, token), ids); err != nil {
+		slog.Warn("Could not cache group IDs", slog.String("error", err.Error()))
+	}
+
+	return ids
+}
+
+// cachedGroupNames returns the cached group names of the account of the
+// default config file.
+func cachedGroupNames() []string {
+	apiUrl, token := defaultAccount()
+	return sortedKeys(cachedGroupIDs(apiUrl, token))
+}
+
+// listGroups walks all pages of the log groups and sorts them by name.
+func listGroups(ctx context.Context, client *Client, limiter *rateLimiter) ([]LogGroup, error) {
+	var groups []LogGroup
+	cursor := ""
+	for {
+		if limiter != nil {
+			if err := limiter.wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		page, err := client.Groups(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		groups = append(groups, page.Groups...)
+		if page.NextPage == "" || len(page.Groups) == 0 {
+			break
+		}
+
+		cursor = page.NextPage
+	}
+
+	slices.SortFunc(groups, func(a, b LogGroup) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return groups, nil
+}
+
+type groupsCommand struct {
+	fs      *flag.FlagSet
+	help    *commandHelp
+	opts    *Options
+	client  *Client
+	limiter *rateLimiter
+	output  *lineWriter
+}
+
+func NewGroupsCommand() *groupsCommand {
+	help := newCommandHelp(groupsCommandName, "list the log groups with their IDs", "")
+	cmd := &groupsCommand{
+		fs:   help.fs,
+		help: help,
+		opts: &Options{},
+	}
+
+	help.boolVar(&cmd.opts.json, "j,json", "Output JSON data")
+	help.stringVar(&cmd.opts.configFile, "c,configfile", defaultConfigFile, "PATH", "Path to config")
+	registerOutputFlags(help, cmd.opts)
+	registerConnectionFlags(help, cmd.opts)
+
+	help.example(
+		"swo-cli groups",
+		"swo-cli logs -g production error",
+	)
+
+	return cmd
+}
+
+func (c *groupsCommand) Init(args []string) error {
+	err := c.help.parse(args)
+	if err != nil {
+		return err
+	}
+
+	if c.fs.NArg() != 0 {
+		return errGroupsArgs
+	}
+
+	opts, err := c.opts.Init([]string{})
+	if err != nil {
+		return err
+	}
+
+	c.client, err = newAPIClient(opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.RateLimit > 0 {
+		c.limiter = newRateLimiter(opts.RateLimit)
+	}
+
+	c.output, err = newOutput(opts)
+	return err
+}
+
+func (c *groupsCommand) Run(ctx context.Context) error {
+	if c.client == nil {
+		return fmt.Errorf("%s command was not initialized", groupsCommandName)
+	}
+
+	err := c.run(ctx)
+
+	return errors.Join(err, c.output.Close())
+}
+
+// run lists all groups, replacing the cached IDs with them.
+func (c *groupsCommand) run(ctx context.Context) error {
+	groups, err := listGroups(ctx, c.client, c.limiter)
+	if err != nil {
+		return err
+	}
+
+	cacheGroupIDs(c.opts.ApiUrl, c.opts.Token, groups)
+	result := GroupsData{Groups: groups}
+
+	if c.opts.json {
+		jsonFormat, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(c.output, string(jsonFormat))
+		return err
+	}
+
+	for _, group := range result.Groups {
+		if _, err := fmt.Fprintf(c.output, "%s %s\n", group.ID, group.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *groupsCommand) Name() string {
+	return groupsCommandName
+}
+
+func (c *groupsCommand) Usage() {
+	c.fs.Usage()
+}
+
+func (c *groupsCommand) Help() *commandHelp {
+	return c.help
+}
@@ -0,0 +1,100 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newGroupsServer(t *testing.T) (*httptest.Server, *int) {
+	requests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/logs/groups", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		data := GroupsData{
+			Groups:   []LogGroup{{ID: "g-2", Name: "staging"}},
+			PageInfo: PageInfo{NextPage: "/v1/logs/groups?skipToken=next"},
+		}
+		if r.URL.Query().Get("skipToken") != "" {
+			data = GroupsData{Groups: []LogGroup{{ID: "g-1", Name: "production"}, {ID: "g-3", Name: "web servers"}}}
+		}
+
+		require.NoError(t, json.NewEncoder(w).Encode(data))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestGroupsCommand(t *testing.T) {
+	server, requests := newGroupsServer(t)
+	createConfigFile(t, configFile, fmt.Sprintf("token: 1234567\napi-url: %s", server.URL))
+
+	output := filepath.Join(t.TempDir(), "output.txt")
+	cmd := NewGroupsCommand()
+	require.NoError(t, cmd.Init([]string{"-c", configFile, "--output-file", output}))
+	require.NoError(t, cmd.Run(context.Background()))
+
+	content, err := os.ReadFile(output)
+	require.NoError(t, err)
+	require.Equal(t, "g-1 production\ng-2 staging\ng-3 web servers\n", string(content))
+	require.Equal(t, map[string]string{"production": "g-1", "staging": "g-2", "web servers": "g-3"}, cachedGroupIDs(server.URL, "1234567"))
+	require.Equal(t, 2, *requests)
+}
+
+func TestResolveGroup(t *testing.T) {
+	server, requests := newGroupsServer(t)
+	createConfigFile(t, configFile, fmt.Sprintf("token: 7654321\napi-url: %s", server.URL))
+
+	// the groups of another account are not used
+	require.NoError(t, writeCacheFile(groupIDsFile(server.URL, "1234567"), map[string]string{"production": "other"}))
+	require.NotEqual(t, groupIDsFile(server.URL, "1234567"), groupIDsFile(server.URL, "7654321"))
+	require.Equal(t, groupIDsFile(server.URL, "1234567"), groupIDsFile(server.URL+"/", "1234567"))
+
+	resolve := func(group string) (string, error) {
+		logs := NewLogsCommand()
+		require.NoError(t, logs.Init([]string{"-c", configFile, "-g", group}))
+		require.Equal(t, group, logs.opts.group, "Init should not resolve the group")
+
+		err := logs.search.resolveGroup(context.Background())
+		return logs.opts.group, err
+	}
+
+	// values that may be IDs are not listed
+	for _, group := range []string{"production", "g-42"} {
+		id, err := resolve(group)
+		require.NoError(t, err)
+		require.Equal(t, group, id)
+	}
+	require.Equal(t, 0, *requests)
+
+	id, err := resolve("web servers")
+	require.NoError(t, err)
+	require.Equal(t, "g-3", id)
+	require.Equal(t, 2, *requests)
+	require.Equal(t, "other", cachedGroupIDs(server.URL, "1234567")["production"])
+
+	id, err = resolve("production")
+	require.NoError(t, err)
+	require.Equal(t, "g-1", id)
+	require.Equal(t, 2, *requests, "cached names should not be listed again")
+
+	_, err = resolve("db servers")
+	require.True(t, errors.Is(err, errUnknownGroup), "error: %v", err)
+	require.Equal(t, 4, *requests)
+}
+
+func TestGroupsInit(t *testing.T) {
+	err := NewGroupsCommand().Init([]string{"production"})
+	require.True(t, errors.Is(err, errGroupsArgs), "error: %v", err)
+}
@@ -0,0 +1,506 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	helpCommandName = "help"
+
+	textFormat     = "text"
+	manFormat      = "man"
+	markdownFormat = "markdown"
+)
+
+var (
+	errHelpFormat  = errors.New("unknown value of the format flag")
+	errHelpArgs    = errors.New("expected at most one command")
+	errHelpCommand = errors.New("unknown command")
+)
+
+// helpFlag is handled by the flag package itself and only documented.
+var helpFlag = &flagHelp{names: []string{"h", "help"}, usage: "Show usage"}
+
+// commandHelp registers the flags of a command together with their
+// documentation, so that usage text, man pages, markdown reference docs
+// and shell completion all come from the same definitions.
+type commandHelp struct {
+	fs       *flag.FlagSet
+	summary  string
+	args     string // positional arguments shown in the synopsis
+	flags    []*flagHelp
+	sections []helpSection
+	examples []string
+}
+
+type flagHelp struct {
+	names  []string // short alias first, e.g. c and configfile
+	arg    string   // placeholder of the value, empty for boolean flags
+	usage  string
+	def    string // shown instead of the default value of the flag
+	global bool   // accepted by every command
+}
+
+// helpSection is an extra block of text, e.g. the key bindings of explore.
+type helpSection struct {
+	title string
+	lines []string
+}
+
+func newCommandHelp(name, summary, args string) *commandHelp {
+	h := &commandHelp{
+		fs:      flag.NewFlagSet(name, flag.ContinueOnError),
+		summary: summary,
+		args:    args,
+	}
+
+	h.fs.Usage = func() {
+		h.writeText(os.Stdout)
+	}
+
+	registerGlobalFlags(h)
+
+	return h
+}
+
+// add registers a flag under every comma separated name in names.
+func (h *commandHelp) add(names, arg, usage string, register func(name string)) *flagHelp {
+	f := &flagHelp{names: strings.Split(names, ","), arg: arg, usage: usage}
+	for _, name := range f.names {
+		register(name)
+	}
+	h.flags = append(h.flags, f)
+
+	return f
+}
+
+func (h *commandHelp) stringVar(p *string, names, value, arg, usage string) *flagHelp {
+	return h.add(names, arg, usage, func(name string) { h.fs.StringVar(p, name, value, usage) })
+}
+
+func (h *commandHelp) intVar(p *int, names string, value int, arg, usage string) *flagHelp {
+	return h.add(names, arg, usage, func(name string) { h.fs.IntVar(p, name, value, usage) })
+}
+
+func (h *commandHelp) float64Var(p *float64, names string, value float64, arg, usage string) *flagHelp {
+	return h.add(names, arg, usage, func(name string) { h.fs.Float64Var(p, name, value, usage) })
+}
+
+func (h *commandHelp) durationVar(p *time.Duration, names string, value time.Duration, arg, usage string) *flagHelp {
+	return h.add(names, arg, usage, func(name string) { h.fs.DurationVar(p, name, value, usage) })
+}
+
+func (h *commandHelp) boolVar(p *bool, names, usage string) *flagHelp {
+	return h.add(names, "", usage, func(name string) { h.fs.BoolVar(p, name, false, usage) })
+}
+
+func (h *commandHelp) variable(value flag.Value, names, arg, usage string) *flagHelp {
+	return h.add(names, arg, usage, func(name string) { h.fs.Var(value, name, usage) })
+}
+
+func (h *commandHelp) section(title string, lines ...string) {
+	h.sections = append(h.sections, helpSection{title: title, lines: lines})
+}
+
+func (h *commandHelp) example(lines ...string) {
+	h.examples = append(h.examples, lines...)
+}
+
+func (h *commandHelp) name() string {
+	return h.fs.Name()
+}
+
+func (h *commandHelp) synopsis() string {
+	parts := []string{cliName}
+	if h.name() != cliName {
+		parts = append(parts, h.name())
+	}
+	if len(h.localFlags()) > 1 {
+		parts = append(parts, "[flags]")
+	}
+	if h.args != "" {
+		parts = append(parts, h.args)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// defaultValue returns the default shown in help. Zero values and boolean
+// flags have none.
+func (h *commandHelp) defaultValue(f *flagHelp) string {
+	if f.def != "" || f == helpFlag {
+		return f.def
+	}
+
+	switch value := h.fs.Lookup(f.names[0]).DefValue; value {
+	case "", "0", "0s", "false":
+		return ""
+	default:
+		return value
+	}
+}
+
+// description returns the usage of f followed by its default.
+func (h *commandHelp) description(f *flagHelp) string {
+	if def := h.defaultValue(f); def != "" {
+		return fmt.Sprintf("%s (%s)", f.usage, def)
+	}
+
+	return f.usage
+}
+
+func (f *flagHelp) options() []string {
+	var options []string
+	for _, name := range f.names {
+		options = append(options, completionFlag{name: name}.option())
+	}
+
+	return options
+}
+
+// spec returns the flag as shown in the text help, e.g. "-c, --configfile PATH".
+func (f *flagHelp) spec() string {
+	spec := strings.Join(f.options(), ", ")
+	if len(f.names[0]) > 1 {
+		spec = "    " + spec
+	}
+	if f.arg != "" {
+		spec += " " + f.arg
+	}
+
+	return spec
+}
+
+// localFlags returns the flags of the command itself, starting with --help.
+func (h *commandHelp) localFlags() []*flagHelp {
+	flags := []*flagHelp{helpFlag}
+	for _, f := range h.flags {
+		if !f.global {
+			flags = append(flags, f)
+		}
+	}
+
+	return flags
+}
+
+func (h *commandHelp) globalFlags() []*flagHelp {
+	var flags []*flagHelp
+	for _, f := range h.flags {
+		if f.global {
+			flags = append(flags, f)
+		}
+	}
+
+	return flags
+}
+
+// writeFlags writes one flag per line with aligned descriptions.
+func (h *commandHelp) writeFlags(b *strings.Builder, flags []*flagHelp, indent string) {
+	width := 0
+	for _, f := range flags {
+		width = max(width, len(f.spec()))
+	}
+
+	for _, f := range flags {
+		fmt.Fprintf(b, "%s%-*s  %s\n", indent, width, f.spec(), h.description(f))
+	}
+}
+
+func (h *commandHelp) writeText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "  %s - %s\n", h.name(), h.summary)
+
+	fmt.Fprintf(&b, "\n    Usage:\n")
+	fmt.Fprintf(&b, "      %s\n", h.synopsis())
+
+	fmt.Fprintf(&b, "\n    Flags:\n")
+	h.writeFlags(&b, h.localFlags(), "      ")
+
+	fmt.Fprintf(&b, "\n    Global flags:\n")
+	h.writeFlags(&b, h.globalFlags(), "      ")
+
+	for _, section := range h.sections {
+		fmt.Fprintf(&b, "\n    %s:\n", section.title)
+		for _, line := range section.lines {
+			fmt.Fprintf(&b, "      %s\n", line)
+		}
+	}
+
+	if len(h.examples) > 0 {
+		fmt.Fprintf(&b, "\n    Examples:\n")
+		for _, example := range h.examples {
+			fmt.Fprintf(&b, "      %s\n", example)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMan writes the sections of a man page. Top level sections use .SH,
+// nested ones .SS. The global flags are only written if global is set.
+func (h *commandHelp) writeMan(b *strings.Builder, heading string, global bool) {
+	fmt.Fprintf(b, "%s SYNOPSIS\n", heading)
+	fmt.Fprintf(b, ".B %s\n", manEscape(h.synopsis()))
+
+	fmt.Fprintf(b, "%s OPTIONS\n", heading)
+	h.writeManFlags(b, h.localFlags())
+
+	if global {
+		fmt.Fprintf(b, "%s GLOBAL OPTIONS\n", heading)
+		h.writeManFlags(b, h.globalFlags())
+	}
+
+	for _, section := range h.sections {
+		fmt.Fprintf(b, "%s %s\n", heading, strings.ToUpper(section.title))
+		fmt.Fprintf(b, ".nf\n")
+		for _, line := range section.lines {
+			fmt.Fprintf(b, "%s\n", manEscape(line))
+		}
+		fmt.Fprintf(b, ".fi\n")
+	}
+
+	if len(h.examples) > 0 {
+		fmt.Fprintf(b, "%s EXAMPLES\n", heading)
+		fmt.Fprintf(b, ".nf\n")
+		for _, example := range h.examples {
+			fmt.Fprintf(b, "%s\n", manEscape(example))
+		}
+		fmt.Fprintf(b, ".fi\n")
+	}
+}
+
+func (h *commandHelp) writeManFlags(b *strings.Builder, flags []*flagHelp) {
+	for _, f := range flags {
+		var options []string
+		for _, option := range f.options() {
+			options = append(options, `\fB`+manEscape(option)+`\fR`)
+		}
+
+		fmt.Fprintf(b, ".TP\n")
+		if f.arg != "" {
+			fmt.Fprintf(b, "%s \\fI%s\\fR\n", strings.Join(options, ", "), manEscape(f.arg))
+		} else {
+			fmt.Fprintf(b, "%s\n", strings.Join(options, ", "))
+		}
+		fmt.Fprintf(b, "%s\n", manEscape(h.description(f)))
+	}
+}
+
+// writeMarkdown writes the sections of a markdown reference under headings
+// of the given level, e.g. "##". The global flags are only written if
+// global is set.
+func (h *commandHelp) writeMarkdown(b *strings.Builder, heading string, global bool) {
+	fmt.Fprintf(b, "%s\n\n", h.summary)
+
+	fmt.Fprintf(b, "%s Usage\n\n", heading)
+	fmt.Fprintf(b, "    %s\n\n", h.synopsis())
+
+	fmt.Fprintf(b, "%s Flags\n\n", heading)
+	h.writeMarkdownFlags(b, h.localFlags())
+
+	if global {
+		fmt.Fprintf(b, "%s Global flags\n\n", heading)
+		h.writeMarkdownFlags(b, h.globalFlags())
+	}
+
+	for _, section := range h.sections {
+		fmt.Fprintf(b, "%s %s\n\n", heading, section.title)
+		for _, line := range section.lines {
+			fmt.Fprintf(b, "    %s\n", line)
+		}
+		fmt.Fprintf(b, "\n")
+	}
+
+	if len(h.examples) > 0 {
+		fmt.Fprintf(b, "%s Examples\n\n", heading)
+		for _, example := range h.examples {
+			fmt.Fprintf(b, "    %s\n", example)
+		}
+		fmt.Fprintf(b, "\n")
+	}
+}
+
+func (h *commandHelp) writeMarkdownFlags(b *strings.Builder, flags []*flagHelp) {
+	fmt.Fprintf(b, "| Flag | Description |\n")
+	fmt.Fprintf(b, "| --- | --- |\n")
+	for _, f := range flags {
+		var options []string
+		for _, option := range f.options() {
+			options = append(options, "`"+option+"`")
+		}
+
+		spec := strings.Join(options, ", ")
+		if f.arg != "" {
+			spec += " `" + f.arg + "`"
+		}
+
+		fmt.Fprintf(b, "| %s | %s |\n", markdownEscape(spec), markdownEscape(h.description(f)))
+	}
+	fmt.Fprintf(b, "\n")
+}
+
+// manEscape escapes text for roff: backslashes and dashes, and a leading
+// dot or quote that would start a request.
+func manEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+
+	return s
+}
+
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+type helpCommand struct {
+	help     *commandHelp
+	registry *Registry
+	format   string
+	target   Command
+}
+
+func NewHelpCommand(registry *Registry) *helpCommand {
+	cmd := &helpCommand{
+		help:     newCommandHelp(helpCommandName, "show help of a command or generate reference docs", "[command]"),
+		registry: registry,
+	}
+
+	cmd.help.stringVar(&cmd.format, "format", textFormat, "[text|man|markdown]", "Format of the help")
+	cmd.help.example(
+		"swo-cli help logs",
+		"swo-cli help cache clear",
+		"swo-cli help --format man > swo-cli.1",
+		"swo-cli help --format markdown export > export.md",
+	)
+
+	return cmd
+}
+
+func (c *helpCommand) Init(args []string) error {
+	err := c.help.parse(args)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains([]string{textFormat, manFormat, markdownFormat}, c.format) {
+		return errHelpFormat
+	}
+
+	if c.help.fs.NArg() > 2 {
+		return errHelpArgs
+	}
+
+	c.target = nil
+	if name := strings.Join(c.help.fs.Args(), " "); name != "" {
+		c.target = c.registry.lookup(name)
+		if c.target == nil {
+			return fmt.Errorf("%w %s", errHelpCommand, name)
+		}
+	}
+
+	return nil
+}
+
+func (c *helpCommand) Run(ctx context.Context) error {
+	return c.write(os.Stdout)
+}
+
+func (c *helpCommand) write(w io.Writer) error {
+	switch {
+	case c.format == manFormat:
+		return c.writeMan(w)
+	case c.format == markdownFormat:
+		return c.writeMarkdown(w)
+	case c.target != nil:
+		return c.target.Help().writeText(w)
+	default:
+		return c.registry.WriteOverview(w)
+	}
+}
+
+// pageName returns the name of the man page or markdown anchor of h.
+func pageName(h *commandHelp) string {
+	return cliName + "-" + strings.ReplaceAll(h.name(), " ", "-")
+}
+
+func (c *helpCommand) writeMan(w io.Writer) error {
+	var b strings.Builder
+	if c.target != nil {
+		h := c.target.Help()
+		fmt.Fprintf(&b, ".TH %s 1\n", strings.ToUpper(pageName(h)))
+		fmt.Fprintf(&b, ".SH NAME\n")
+		fmt.Fprintf(&b, "%s \\- %s\n", manEscape(pageName(h)), manEscape(h.summary))
+		h.writeMan(&b, ".SH", true)
+	} else {
+		fmt.Fprintf(&b, ".TH %s 1\n", strings.ToUpper(cliName))
+		fmt.Fprintf(&b, ".SH NAME\n")
+		fmt.Fprintf(&b, "%s \\- command-line search for SolarWinds Observability logs\n", manEscape(cliName))
+		fmt.Fprintf(&b, ".SH SYNOPSIS\n")
+		fmt.Fprintf(&b, ".B %s\n", manEscape(c.registry.help.synopsis()))
+		fmt.Fprintf(&b, ".SH GLOBAL OPTIONS\n")
+		c.registry.help.writeManFlags(&b, c.registry.help.globalFlags())
+		for _, cmd := range c.registry.commands {
+			h := cmd.Help()
+			fmt.Fprintf(&b, ".SH %s\n", manEscape(strings.ToUpper(cliName+" "+h.name())))
+			fmt.Fprintf(&b, "%s\n", manEscape(h.summary))
+			h.writeMan(&b, ".SS", false)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (c *helpCommand) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+	if c.target != nil {
+		h := c.target.Help()
+		fmt.Fprintf(&b, "# %s %s\n\n", cliName, h.name())
+		h.writeMarkdown(&b, "##", true)
+	} else {
+		fmt.Fprintf(&b, "# %s\n\n", cliName)
+		fmt.Fprintf(&b, "    %s\n\n", c.registry.help.synopsis())
+		fmt.Fprintf(&b, "| Command | Description |\n")
+		fmt.Fprintf(&b, "| --- | --- |\n")
+		for _, cmd := range c.registry.commands {
+			fmt.Fprintf(&b, "| [%s](#%s) | %s |\n", cmd.Name(), pageName(cmd.Help()), markdownEscape(cmd.Help().summary))
+		}
+		fmt.Fprintf(&b, "\n")
+
+		fmt.Fprintf(&b, "## Global flags\n\n")
+		c.registry.help.writeMarkdownFlags(&b, c.registry.help.globalFlags())
+
+		for _, cmd := range c.registry.commands {
+			h := cmd.Help()
+			fmt.Fprintf(&b, "## %s %s\n\n", cliName, h.name())
+			h.writeMarkdown(&b, "###", false)
+		}
+	}
+
+	_, err := io.WriteString(w, strings.TrimSuffix(b.String(), "\n"))
+	return err
+}
+
+func (c *helpCommand) Name() string {
+	return helpCommandName
+}
+
+func (c *helpCommand) Usage() {
+	c.help.fs.Usage()
+}
+
+func (c *helpCommand) Help() *commandHelp {
+	return c.help
+}
@@ -0,0 +1,133 @@
+package logs
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func helpOutput(t *testing.T, args ...string) string {
+	cmd := NewHelpCommand(testRegistry())
+	require.NoError(t, cmd.Init(args))
+
+	var output strings.Builder
+	require.NoError(t, cmd.write(&output))
+
+	return output.String()
+}
+
+func TestHelpRegistry(t *testing.T) {
+	h := newCommandHelp("test", "test command", "[query]")
+
+	var config string
+	var count int
+	var json bool
+	h.stringVar(&config, "c,configfile", "config.yaml", "PATH", "Path to config")
+	h.intVar(&count, "count", 0, "NUMBER", "Number of logs").def = "all"
+	h.boolVar(&json, "j,json", "Output JSON")
+
+	require.NoError(t, h.fs.Parse([]string{"-c", "one.yaml", "--json", "--count", "3", "query"}))
+	require.Equal(t, "one.yaml", config)
+	require.Equal(t, 3, count)
+	require.True(t, json)
+
+	require.Equal(t, "Path to config (config.yaml)", h.description(h.flags[3]))
+	require.Equal(t, "Number of logs (all)", h.description(h.flags[4]))
+	require.Equal(t, "Output JSON", h.description(h.flags[5]))
+
+	var output strings.Builder
+	require.NoError(t, h.writeText(&output))
+	require.Equal(t, `  test - test command
+
+    Usage:
+      swo-cli test [flags] [query]
+
+    Flags:
+      -h, --help             Show usage
+      -c, --configfile PATH  Path to config (config.yaml)
+          --count NUMBER     Number of logs (all)
+      -j, --json             Output JSON
+
+    Global flags:
+          --profile NAME   Use the settings of profile NAME of the config file
+          --debug          Log debug information, e.g. response sizes
+          --output FORMAT  Output format of commands with JSON output: text or json
+`, output.String())
+}
+
+// Every registered flag must be documented, so that help cannot drift from
+// the flags that are actually parsed.
+func TestHelpDocumentsAllFlags(t *testing.T) {
+	registry := testRegistry()
+	registry.Register(NewExportCommand())
+	registry.Register(NewFormatCommand())
+
+	for _, target := range registry.commands {
+		h := target.Help()
+		documented := map[string]bool{}
+		for _, f := range h.flags {
+			require.NotEmpty(t, f.usage, "%s %v", target.Name(), f.names)
+			for _, name := range f.names {
+				documented[name] = true
+			}
+		}
+
+		h.fs.VisitAll(func(f *flag.Flag) {
+			require.True(t, documented[f.Name], "%s --%s is not documented", target.Name(), f.Name)
+		})
+	}
+}
+
+func TestHelpCommand(t *testing.T) {
+	overview := helpOutput(t)
+	require.Contains(t, overview, "  logs             command-line search for SolarWinds Observability log management service\n")
+	require.Contains(t, overview, "  histogram, hist  ")
+	require.Contains(t, overview, "  cache clear      remove the cached search results and names\n")
+	require.Contains(t, overview, "Global flags:\n      --profile NAME")
+
+	text := helpOutput(t, "cache", "clear")
+	require.True(t, strings.HasPrefix(text, "  cache clear - remove the cached search results and names\n"))
+	require.Contains(t, text, "      swo-cli cache clear\n")
+	require.Equal(t, text, helpOutput(t, "cache clear"))
+	require.Contains(t, helpOutput(t, "hist"), "  histogram - ")
+
+	text = helpOutput(t, "explore")
+	require.True(t, strings.HasPrefix(text, "  explore - browse logs in a full-screen terminal UI\n"))
+	require.Contains(t, text, "      swo-cli explore [flags] [--] [query]\n")
+	require.Contains(t, text, "          --api-url URL ")
+	require.Contains(t, text, "    Keys:\n")
+
+	man := helpOutput(t, "--format", "man", "logs")
+	require.True(t, strings.HasPrefix(man, ".TH SWO-CLI-LOGS 1\n.SH NAME\nswo\\-cli\\-logs \\- "))
+	require.Contains(t, man, ".TP\n\\fB\\-c\\fR, \\fB\\-\\-configfile\\fR \\fIPATH\\fR\nPath to config (~/.swo\\-cli.yaml)\n")
+	require.Contains(t, man, "swo\\-cli logs \\-\\-grep 'timeout after \\ed+ms'")
+
+	man = helpOutput(t, "--format", "man")
+	require.Contains(t, man, ".SH SWO\\-CLI CACHE CLEAR\n")
+	require.Contains(t, man, ".SH GLOBAL OPTIONS\n")
+	require.Contains(t, man, ".SS OPTIONS\n")
+
+	markdown := helpOutput(t, "--format", "markdown")
+	require.True(t, strings.HasPrefix(markdown, "# swo-cli\n"))
+	require.Contains(t, markdown, "| [cache clear](#swo-cli-cache-clear) | remove the cached search results and names |\n")
+	require.Contains(t, markdown, "## Global flags\n")
+	require.Equal(t, 1, strings.Count(markdown, "`--profile`"))
+	require.Contains(t, markdown, "## swo-cli explore\n")
+	require.Contains(t, markdown, "| `--color` `[program\\|system\\|all\\|off]` | ")
+	require.Contains(t, markdown, "### Keys\n")
+}
+
+func TestHelpInit(t *testing.T) {
+	registry := testRegistry()
+	err := NewHelpCommand(registry).Init([]string{"--format", "pdf"})
+	require.True(t, errors.Is(err, errHelpFormat), "error: %v", err)
+
+	err = NewHelpCommand(registry).Init([]string{"cache", "clear", "now"})
+	require.True(t, errors.Is(err, errHelpArgs), "error: %v", err)
+
+	err = NewHelpCommand(registry).Init([]string{"tail"})
+	require.True(t, errors.Is(err, errHelpCommand), "error: %v", err)
+}
@@ -0,0 +1,423 @@
+package logs
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	histogramCommandName = "histogram"
+
+	defaultHistogramBuckets = 30
+	maxHistogramBuckets     = 5000
+	histogramBarWidth       = 50
+)
+
+var (
+	errIntervalFlag    = errors.New("--interval must not be negative")
+	errHistogramMode   = errors.New("--json and --csv are mutually exclusive")
+	errHistogramWindow = errors.New("--max-time must not be before --min-time")
+	errHistogramBucket = errors.New("--interval is too small for the time range")
+
+	sparks = []rune("▁▂▃▄▅▆▇█")
+)
+
+type histogramCommand struct {
+	fs     *flag.FlagSet
+	help   *commandHelp
+	search *searcher
+	opts   *Options
+
+	interval   time.Duration
+	sparkline  bool
+	bySeverity bool
+	csv        bool
+}
+
+type HistogramBucket struct {
+	Start      time.Time      `json:"start"`
+	Count      int            `json:"count"`
+	Severities map[string]int `json:"severities,omitempty"`
+}
+
+type Histogram struct {
+	Interval time.Duration     `json:"-"`
+	Buckets  []HistogramBucket `json:"buckets"`
+}
+
+func NewHistogramCommand() *histogramCommand {
+	help := newCommandHelp(histogramCommandName, "chart of log volume over time", "[--] [query]")
+	cmd := &histogramCommand{
+		fs:   help.fs,
+		help: help,
+		opts: &Options{},
+	}
+
+	help.durationVar(&cmd.interval, "interval", 0, "DURATION", "Bucket size").def = "time range split into 30 buckets"
+	help.boolVar(&cmd.sparkline, "sparkline", "Render a sparkline instead of a bar chart")
+	help.boolVar(&cmd.bySeverity, "by-severity", "Split the chart by severity")
+	help.intVar(&cmd.opts.count, "count", defaultStatsCount, "NUMBER", "Number of log entries to analyze")
+	help.boolVar(&cmd.opts.json, "j,json", "Output buckets as JSON data")
+	help.boolVar(&cmd.csv, "csv", "Output buckets as CSV")
+	registerSearchFlags(help, cmd.opts, "count")
+	registerParallelFlags(help, cmd.opts)
+	registerOutputFlags(help, cmd.opts)
+	registerConnectionFlags(help, cmd.opts)
+
+	help.example(
+		"swo-cli histogram --min-time '1 hour ago' --interval 5m",
+		"swo-cli histogram --min-time '1 day ago' --sparkline --by-severity",
+		"swo-cli histogram --min-time '1 day ago' --interval 1h --csv > volume.csv",
+	)
+
+	return cmd
+}
+
+func (c *histogramCommand) Init(args []string) error {
+	err := c.help.parse(args)
+	if err != nil {
+		return err
+	}
+
+	if c.interval < 0 {
+		return errIntervalFlag
+	}
+	if c.csv && c.opts.json {
+		return errHistogramMode
+	}
+
+	opts, err := c.opts.Init(c.fs.Args())
+	if err != nil {
+		return err
+	}
+
+	if c.interval > 0 && opts.minTime != "" {
+		start, err := time.Parse(time.RFC3339, opts.minTime)
+		if err != nil {
+			return err
+		}
+
+		end := now
+		if opts.maxTime != "" {
+			end, err = time.Parse(time.RFC3339, opts.maxTime)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := checkHistogramBuckets(start, end, c.interval); err != nil {
+			return err
+		}
+	}
+
+	search, err := newSearcher(opts)
+	if err != nil {
+		return err
+	}
+
+	c.search = search
+
+	return nil
+}
+
+// checkHistogramBuckets rejects an interval that splits the window into
+// more than maxHistogramBuckets buckets.
+func checkHistogramBuckets(start, end time.Time, interval time.Duration) error {
+	if n := int64(end.Sub(start)/interval) + 1; n > maxHistogramBuckets {
+		return fmt.Errorf("%w: %d buckets, at most %d", errHistogramBucket, n, maxHistogramBuckets)
+	}
+
+	return nil
+}
+
+func (c *histogramCommand) Run(ctx context.Context) error {
+	if c.search == nil {
+		return fmt.Errorf("%s command was not initialized", histogramCommandName)
+	}
+
+	err := c.run(ctx)
+
+	return errors.Join(err, c.search.Close())
+}
+
+func (c *histogramCommand) run(ctx context.Context) error {
+	if err := c.search.resolveGroup(ctx); err != nil {
+		return err
+	}
+
+	q, err := c.search.query()
+	if err != nil {
+		return fmt.Errorf("error while preparing http request to SWO: %w", err)
+	}
+
+	logs, err := c.search.fetch(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	start, end, err := c.window(logs.Logs)
+	if err != nil {
+		return err
+	}
+
+	// Only the newest --count logs were fetched, so the buckets before the
+	// oldest of them would wrongly show no logs.
+	if len(logs.Logs) >= c.opts.count {
+		oldest := logs.Logs[0].Time
+		for _, l := range logs.Logs {
+			if l.Time.Before(oldest) {
+				oldest = l.Time
+			}
+		}
+
+		if oldest.After(start) {
+			slog.Warn("Reached --count, the histogram starts at the oldest fetched log",
+				slog.Int("count", c.opts.count), slog.Time("start", oldest))
+			start = oldest
+		}
+	}
+	if start.IsZero() || end.IsZero() {
+		return nil
+	}
+	if end.Before(start) {
+		return errHistogramWindow
+	}
+	if c.interval > 0 {
+		if err := checkHistogramBuckets(start, end, c.interval); err != nil {
+			return err
+		}
+	}
+
+	histogram := computeHistogram(logs.Logs, start, end, c.interval, c.bySeverity)
+	switch {
+	case c.opts.json:
+		jsonFormat, err := json.Marshal(struct {
+			Interval string `json:"interval"`
+			*Histogram
+		}{histogram.Interval.String(), histogram})
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(c.search.output, string(jsonFormat))
+		return err
+	case c.csv:
+		return writeHistogramCSV(c.search.output, histogram)
+	case c.sparkline:
+		return writeSparklines(c.search.output, histogram, c.bySeverity)
+	default:
+		return writeBars(c.search.output, histogram, c.bySeverity)
+	}
+}
+
+func (c *histogramCommand) Name() string {
+	return histogramCommandName
+}
+
+func (c *histogramCommand) Usage() {
+	c.fs.Usage()
+}
+
+// window returns the --min-time/--max-time range, falling back to the
+// oldest and newest fetched log when a bound was not provided.
+func (c *histogramCommand) window(logs []Log) (time.Time, time.Time, error) {
+	var start, end time.Time
+	for _, l := range logs {
+		if start.IsZero() || l.Time.Before(start) {
+			start = l.Time
+		}
+		if end.IsZero() || l.Time.After(end) {
+			end = l.Time
+		}
+	}
+
+	if c.opts.minTime != "" {
+		t, err := time.Parse(time.RFC3339, c.opts.minTime)
+		if err != nil {
+			return start, end, err
+		}
+		start = t
+	}
+
+	if c.opts.maxTime != "" {
+		t, err := time.Parse(time.RFC3339, c.opts.maxTime)
+		if err != nil {
+			return start, end, err
+		}
+		end = t
+	}
+
+	return start, end, nil
+}
+
+// computeHistogram counts logs in consecutive interval-wide buckets starting
+// at start. A zero interval splits the window into defaultHistogramBuckets.
+func computeHistogram(logs []Log, start, end time.Time, interval time.Duration, bySeverity bool) *Histogram {
+	if interval == 0 {
+		interval = max(end.Sub(start)/defaultHistogramBuckets, time.Second).Truncate(time.Second)
+	}
+
+	n := int(end.Sub(start)/interval) + 1
+	histogram := &Histogram{Interval: interval, Buckets: make([]HistogramBucket, n)}
+	for i := range histogram.Buckets {
+		histogram.Buckets[i].Start = start.Add(time.Duration(i) * interval)
+		if bySeverity {
+			histogram.Buckets[i].Severities = make(map[string]int)
+		}
+	}
+
+	for _, l := range logs {
+		if l.Time.Before(start) || l.Time.After(end) {
+			continue
+		}
+
+		bucket := &histogram.Buckets[int(l.Time.Sub(start)/interval)]
+		bucket.Count++
+		if bySeverity {
+			bucket.Severities[l.Severity]++
+		}
+	}
+
+	return histogram
+}
+
+func (h *Histogram) severities() []string {
+	var severities []string
+	for _, b := range h.Buckets {
+		for s := range b.Severities {
+			if !slices.Contains(severities, s) {
+				severities = append(severities, s)
+			}
+		}
+	}
+
+	slices.Sort(severities)
+	return severities
+}
+
+// series returns the bucket counts for a severity, or the totals when
+// severity is empty.
+func (h *Histogram) series(severity string) []int {
+	counts := make([]int, len(h.Buckets))
+	for i, b := range h.Buckets {
+		if severity == "" {
+			counts[i] = b.Count
+		} else {
+			counts[i] = b.Severities[severity]
+		}
+	}
+
+	return counts
+}
+
+func writeBars(w io.Writer, h *Histogram, bySeverity bool) error {
+	names := []string{""}
+	if bySeverity {
+		names = h.severities()
+	}
+
+	for i, name := range names {
+		if bySeverity {
+			if i != 0 {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintln(w, name); err != nil {
+				return err
+			}
+		}
+
+		counts := h.series(name)
+		peak := slices.Max(counts)
+		for j, count := range counts {
+			width := 0
+			if peak > 0 {
+				width = count * histogramBarWidth / peak
+			}
+			if count > 0 && width == 0 {
+				width = 1
+			}
+
+			label := h.Buckets[j].Start.In(time.Local).Format("Jan 02 15:04:05")
+			if _, err := fmt.Fprintf(w, "%s %s %d\n", label, strings.Repeat("█", width), count); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeSparklines(w io.Writer, h *Histogram, bySeverity bool) error {
+	names := []string{""}
+	if bySeverity {
+		names = h.severities()
+	}
+
+	from := h.Buckets[0].Start.In(time.Local).Format("Jan 02 15:04:05")
+	to := h.Buckets[len(h.Buckets)-1].Start.Add(h.Interval).In(time.Local).Format("Jan 02 15:04:05")
+	if _, err := fmt.Fprintf(w, "%s - %s, %s per character\n", from, to, h.Interval); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		counts := h.series(name)
+		peak := slices.Max(counts)
+
+		line := make([]rune, len(counts))
+		for i, count := range counts {
+			line[i] = sparks[0]
+			if peak > 0 {
+				line[i] = sparks[count*(len(sparks)-1)/peak]
+			}
+		}
+
+		label := ""
+		if bySeverity {
+			label = name + " "
+		}
+		if _, err := fmt.Fprintf(w, "%s%s max %d\n", label, string(line), peak); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeHistogramCSV(w io.Writer, h *Histogram) error {
+	severities := h.severities()
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append([]string{"start", "count"}, severities...)); err != nil {
+		return err
+	}
+
+	for _, b := range h.Buckets {
+		record := []string{b.Start.Format(time.RFC3339), strconv.Itoa(b.Count)}
+		for _, s := range severities {
+			record = append(record, strconv.Itoa(b.Severities[s]))
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (c *histogramCommand) Help() *commandHelp {
+	return c.help
+}
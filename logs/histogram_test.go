@@ -0,0 +1,111 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeHistogram(t *testing.T) {
+	location, err := time.LoadLocation("GMT")
+	require.NoError(t, err)
+
+	time.Local = location
+
+	start, err := time.Parse(time.RFC3339, "2000-01-01T10:00:00Z")
+	require.NoError(t, err)
+	end := start.Add(30 * time.Minute)
+
+	logs := []Log{
+		{Time: start.Add(time.Minute), Severity: "INFO"},
+		{Time: start.Add(2 * time.Minute), Severity: "ERROR"},
+		{Time: start.Add(12 * time.Minute), Severity: "INFO"},
+		{Time: start.Add(40 * time.Minute), Severity: "INFO"},
+	}
+
+	histogram := computeHistogram(logs, start, end, 10*time.Minute, true)
+	require.Equal(t, 10*time.Minute, histogram.Interval)
+	require.Len(t, histogram.Buckets, 4)
+	require.Equal(t, []int{2, 1, 0, 0}, histogram.series(""))
+	require.Equal(t, []int{1, 0, 0, 0}, histogram.series("ERROR"))
+	require.Equal(t, []string{"ERROR", "INFO"}, histogram.severities())
+
+	auto := computeHistogram(logs, start, end, 0, false)
+	require.Equal(t, time.Minute, auto.Interval)
+	require.Len(t, auto.Buckets, 31)
+
+	buf := &bytes.Buffer{}
+	err = writeHistogramCSV(buf, histogram)
+	require.NoError(t, err)
+	require.Equal(t, `start,count,ERROR,INFO
+2000-01-01T10:00:00Z,2,1,1
+2000-01-01T10:10:00Z,1,0,1
+2000-01-01T10:20:00Z,0,0,0
+2000-01-01T10:30:00Z,0,0,0
+`, buf.String())
+
+	buf.Reset()
+	err = writeSparklines(buf, histogram, false)
+	require.NoError(t, err)
+	require.Equal(t, "Jan 01 10:00:00 - Jan 01 10:40:00, 10m0s per character\n█▄▁▁ max 2\n", buf.String())
+
+	buf.Reset()
+	err = writeBars(buf, histogram, false)
+	require.NoError(t, err)
+	require.Equal(t, "Jan 01 10:00:00 "+string(bytes.Repeat([]byte("█"), 50))+" 2\n"+
+		"Jan 01 10:10:00 "+string(bytes.Repeat([]byte("█"), 25))+" 1\n"+
+		"Jan 01 10:20:00  0\n"+
+		"Jan 01 10:30:00  0\n", buf.String())
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	createConfigFile(t, configFile, "token: 1234567")
+	window := []string{"--configfile", configFile, "--min-time", "2026-10-10T00:00:00Z", "--max-time", "2026-10-17T12:00:00Z"}
+
+	for _, interval := range []string{"1ms", "1s", "1m"} {
+		err := NewHistogramCommand().Init(append(window, "--interval", interval))
+		require.True(t, errors.Is(err, errHistogramBucket), "%s error: %v", interval, err)
+	}
+
+	require.NoError(t, NewHistogramCommand().Init(append(window, "--interval", "1h")))
+	require.NoError(t, NewHistogramCommand().Init(window))
+
+	start := time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, checkHistogramBuckets(start, start.Add((maxHistogramBuckets-1)*time.Minute), time.Minute))
+	require.Error(t, checkHistogramBuckets(start, start.Add(maxHistogramBuckets*time.Minute), time.Minute))
+}
+
+func TestHistogramCountReached(t *testing.T) {
+	start := time.Date(2000, 1, 1, 10, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := LogsData{PageInfo: PageInfo{NextPage: "/v1/logs?page=1"}}
+		for _, minutes := range []int{50, 40, 30} {
+			data.Logs = append(data.Logs, Log{Time: start.Add(time.Duration(minutes) * time.Minute)})
+		}
+
+		require.NoError(t, json.NewEncoder(w).Encode(data))
+	}))
+	t.Cleanup(server.Close)
+	createConfigFile(t, configFile, fmt.Sprintf("token: 1234567\napi-url: %s", server.URL))
+
+	cmd := NewHistogramCommand()
+	require.NoError(t, cmd.Init([]string{"--configfile", configFile, "--count", "3", "--interval", "10m", "--csv",
+		"--min-time", "2000-01-01T10:00:00Z", "--max-time", "2000-01-01T11:00:00Z"}))
+
+	var output bytes.Buffer
+	cmd.search.output = &output
+	require.NoError(t, cmd.run(context.Background()))
+	require.Equal(t, "start,count\n"+
+		"2000-01-01T10:30:00Z,1\n"+
+		"2000-01-01T10:40:00Z,1\n"+
+		"2000-01-01T10:50:00Z,1\n"+
+		"2000-01-01T11:00:00Z,0\n", output.String())
+}
@@ -0,0 +1,53 @@
+package logs
+
+import (
+	"context"
+)
+
+// All returns an iterator over the logs matching q, newest first, that
+// lazily follows PageInfo.NextPage. Iteration stops after limit logs when
+// limit is positive, when the caller returns false, or after yielding an
+// error, including the context being canceled.
+//
+// The returned function has the shape of iter.Seq2[Log, error], so on Go
+// 1.23+ it can be ranged over directly. On Go 1.22 call it with the loop
+// body as a callback:
+//
+//	client.All(ctx, q, 500)(func(l logs.Log, err error) bool {
+//		...
+//		return true
+//	})
+func (c *Client) All(ctx context.Context, q Query, limit int) func(yield func(Log, error) bool) {
+	return func(yield func(Log, error) bool) {
+		count := 0
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(Log{}, err)
+				return
+			}
+
+			page, err := c.Search(ctx, q)
+			if err != nil {
+				yield(Log{}, err)
+				return
+			}
+
+			for _, l := range page.Logs {
+				if !yield(l, nil) {
+					return
+				}
+
+				count++
+				if limit > 0 && count >= limit {
+					return
+				}
+			}
+
+			if page.NextPage == "" || len(page.Logs) == 0 {
+				return
+			}
+
+			q.Cursor = page.NextPage
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newPagedServer(t *testing.T, pages int, perPage int) (*httptest.Server, *int) {
+	requests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/logs", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		var data LogsData
+		for i := 0; i < perPage; i++ {
+			data.Logs = append(data.Logs, Log{Message: fmt.Sprintf("page %d log %d", page, i)})
+		}
+		if page+1 < pages {
+			data.NextPage = fmt.Sprintf("/v1/logs?page=%d", page+1)
+		}
+
+		content, err := json.Marshal(data)
+		require.NoError(t, err)
+
+		_, err = w.Write(content)
+		require.NoError(t, err)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestAll(t *testing.T) {
+	server, requests := newPagedServer(t, 3, 2)
+
+	client, err := NewClient(WithToken("1234567"), WithApiUrl(server.URL))
+	require.NoError(t, err)
+
+	var messages []string
+	client.All(context.Background(), Query{PageSize: 2}, 0)(func(l Log, err error) bool {
+		require.NoError(t, err)
+		messages = append(messages, l.Message)
+		return true
+	})
+	require.Equal(t, []string{
+		"page 0 log 0", "page 0 log 1",
+		"page 1 log 0", "page 1 log 1",
+		"page 2 log 0", "page 2 log 1",
+	}, messages)
+	require.Equal(t, 3, *requests)
+
+	*requests = 0
+	messages = nil
+	client.All(context.Background(), Query{PageSize: 2}, 3)(func(l Log, err error) bool {
+		require.NoError(t, err)
+		messages = append(messages, l.Message)
+		return true
+	})
+	require.Equal(t, []string{"page 0 log 0", "page 0 log 1", "page 1 log 0"}, messages)
+	require.Equal(t, 2, *requests, "pages after the limit should not be fetched")
+
+	*requests = 0
+	messages = nil
+	client.All(context.Background(), Query{PageSize: 2}, 0)(func(l Log, err error) bool {
+		require.NoError(t, err)
+		messages = append(messages, l.Message)
+		return false
+	})
+	require.Equal(t, []string{"page 0 log 0"}, messages)
+	require.Equal(t, 1, *requests)
+}
+
+func TestAllCanceled(t *testing.T) {
+	server, requests := newPagedServer(t, 3, 2)
+
+	client, err := NewClient(WithToken("1234567"), WithApiUrl(server.URL))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var errs []error
+	count := 0
+	client.All(ctx, Query{PageSize: 2}, 0)(func(_ Log, err error) bool {
+		if err != nil {
+			errs = append(errs, err)
+			return true
+		}
+
+		count++
+		if count == 2 {
+			cancel()
+		}
+		return true
+	})
+
+	require.Equal(t, 2, count)
+	require.Len(t, errs, 1)
+	require.True(t, errors.Is(errs[0], context.Canceled))
+	require.Equal(t, 1, *requests)
+}
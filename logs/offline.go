@@ -0,0 +1,163 @@
+package logs
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const offlineCursorParam = "offset"
+
+var errFromFile = errors.New("failed to read --from-file")
+
+// offlineSource answers queries from previously exported or printed logs
+// instead of the SWO API.
+type offlineSource struct {
+	logs []Log
+}
+
+// offlineEntry decodes both NDJSON lines written by export and LogsData
+// objects printed by --json.
+type offlineEntry struct {
+	Log
+	Logs []Log `json:"logs"`
+}
+
+// newOfflineSource reads every file matching pattern, or stdin for "-".
+// Files ending in .gz are decompressed.
+func newOfflineSource(pattern string) (*offlineSource, error) {
+	src := &offlineSource{}
+
+	if pattern == "-" {
+		if err := src.read(os.Stdin); err != nil {
+			return nil, errors.Join(errFromFile, err)
+		}
+	} else {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, errors.Join(errFromFile, err)
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("%w: no file matches %s", errFromFile, pattern)
+		}
+
+		for _, path := range paths {
+			if err := src.readFile(path); err != nil {
+				return nil, fmt.Errorf("%w: %s: %w", errFromFile, path, err)
+			}
+		}
+	}
+
+	// order the logs newest first like the SWO API
+	slices.SortStableFunc(src.logs, func(a, b Log) int {
+		return b.Time.Compare(a.Time)
+	})
+
+	return src, nil
+}
+
+func (src *offlineSource) readFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+
+		r = gz
+	}
+
+	return src.read(r)
+}
+
+func (src *offlineSource) read(r io.Reader) error {
+	decoder := json.NewDecoder(r)
+	for {
+		var entry offlineEntry
+		err := decoder.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if entry.Logs != nil {
+			src.logs = append(src.logs, entry.Logs...)
+		} else {
+			src.logs = append(src.logs, entry.Log)
+		}
+	}
+}
+
+// search evaluates q like the API would, including paging: the cursor of
+// the next page holds the offset into the matching logs.
+func (src *offlineSource) search(q Query) (*LogsData, error) {
+	offset := 0
+	if q.Cursor != "" {
+		cursor, err := url.Parse(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		offset, err = strconv.Atoi(cursor.Query().Get(offlineCursorParam))
+		if err != nil {
+			return nil, fmt.Errorf("invalid offline cursor %q: %w", q.Cursor, err)
+		}
+	}
+
+	node, err := parseQuery(q.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := q.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultCount
+	}
+
+	var (
+		result  LogsData
+		matched int
+	)
+	for _, l := range src.logs {
+		if !q.StartTime.IsZero() && l.Time.Before(q.StartTime) {
+			continue
+		}
+		if !q.EndTime.IsZero() && l.Time.After(q.EndTime) {
+			continue
+		}
+		if node != nil && !node.match(l) {
+			continue
+		}
+
+		matched++
+		if matched <= offset {
+			continue
+		}
+
+		if len(result.Logs) == pageSize {
+			result.NextPage = fmt.Sprintf("/v1/logs?%s=%d", offlineCursorParam, offset+pageSize)
+			break
+		}
+
+		result.Logs = append(result.Logs, l)
+	}
+
+	return &result, nil
+}
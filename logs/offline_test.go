@@ -0,0 +1,168 @@
+package logs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryMatch(t *testing.T) {
+	l := Log{Message: "Connection refused from 1.2.3.4 user:alice at 10:00:30 for http://example.com/login", Hostname: "www42", Program: "nginx", Severity: "ERROR"}
+
+	testCases := []struct {
+		query    string
+		expected bool
+	}{
+		{query: "refused", expected: true},
+		{query: "REFUSED", expected: true},
+		{query: "accepted", expected: false},
+		{query: `"connection refused"`, expected: true},
+		{query: `"refused connection"`, expected: false},
+		{query: "host:www42", expected: true},
+		{query: "host:WWW42", expected: true},
+		{query: "host:www4", expected: false},
+		{query: "host:www*", expected: true},
+		{query: "program:sshd", expected: false},
+		{query: "attribute:value", expected: false},
+		{query: "user:alice", expected: true},
+		{query: "user:bob", expected: false},
+		{query: "10:00:30", expected: true},
+		{query: "http://example.com/login", expected: true},
+		{query: "refused 1.2.3.4", expected: true},
+		{query: "refused AND accepted", expected: false},
+		{query: "accepted OR refused", expected: true},
+		{query: "-accepted", expected: true},
+		{query: "refused -host:www42", expected: false},
+		{query: "(accepted OR refused) severity:error", expected: true},
+		{query: "-(accepted OR refused)", expected: false},
+		{query: "www42", expected: true},
+		{query: "NGINX", expected: true},
+		{query: `"www42 nginx"`, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			node, err := parseQuery(tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, node.match(l))
+		})
+	}
+}
+
+func TestQueryMatchExamples(t *testing.T) {
+	logs := []Log{
+		{Hostname: "www42", Program: "nginx", Message: "GET /index.html 200"},
+		{Hostname: "acmedb-core01", Program: "pgsql", Message: "connection accepted"},
+		{Hostname: "db3", Program: "pgsql", Message: "checkpoint starting"},
+		{Hostname: "web1", Program: "sshd", Message: "www42 nginx restarted"},
+	}
+
+	testCases := []struct {
+		query    string
+		expected []bool
+	}{
+		{query: "(www OR db) (nginx OR pgsql) -accepted", expected: []bool{true, false, true, true}},
+		{query: "www42", expected: []bool{true, false, false, true}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			node, err := parseQuery(tc.query)
+			require.NoError(t, err)
+
+			for i, l := range logs {
+				require.Equal(t, tc.expected[i], node.match(l), "%+v", l)
+			}
+		})
+	}
+}
+
+func writeOfflineFiles(t *testing.T, base time.Time) string {
+	dir := t.TempDir()
+
+	var ndjson bytes.Buffer
+	for i, message := range []string{"one", "two", "three"} {
+		line, err := json.Marshal(Log{Time: base.Add(time.Duration(i) * time.Minute), Message: message, Hostname: "www42"})
+		require.NoError(t, err)
+		ndjson.Write(append(line, '\n'))
+	}
+
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	_, err := gz.Write(ndjson.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "export-0001.ndjson.gz"), gzipped.Bytes(), 0o644))
+
+	page, err := json.Marshal(LogsData{Logs: []Log{
+		{Time: base.Add(5 * time.Minute), Message: "five", Hostname: "db01"},
+		{Time: base.Add(4 * time.Minute), Message: "four", Hostname: "www42"},
+	}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "export-0002.ndjson"), append(page, '\n'), 0o644))
+
+	return dir
+}
+
+func TestOfflineSource(t *testing.T) {
+	base, err := time.Parse(time.RFC3339, "2000-01-01T10:00:00Z")
+	require.NoError(t, err)
+
+	dir := writeOfflineFiles(t, base)
+
+	src, err := newOfflineSource(filepath.Join(dir, "export-*"))
+	require.NoError(t, err)
+	require.Len(t, src.logs, 5)
+
+	q := Query{Filter: "host:www42", StartTime: base.Add(time.Minute), PageSize: 2}
+	page, err := src.search(q)
+	require.NoError(t, err)
+	require.Equal(t, []string{"four", "three"}, messages(page.Logs))
+	require.NotEmpty(t, page.NextPage)
+
+	q.Cursor = page.NextPage
+	page, err = src.search(q)
+	require.NoError(t, err)
+	require.Equal(t, []string{"two"}, messages(page.Logs))
+	require.Empty(t, page.NextPage)
+
+	_, err = newOfflineSource(filepath.Join(dir, "missing-*"))
+	require.True(t, errors.Is(err, errFromFile), "error: %v", err)
+}
+
+func TestRunOffline(t *testing.T) {
+	base, err := time.Parse(time.RFC3339, "2000-01-01T10:00:00Z")
+	require.NoError(t, err)
+
+	dir := writeOfflineFiles(t, base)
+	createConfigFile(t, configFile, "")
+
+	cmd := NewLogsCommand()
+	err = cmd.Init([]string{"--configfile", configFile, "--offline", filepath.Join(dir, "*"), "--count", "3", "--max-time", "2000-01-01T10:04:30Z", "--json", "--", "-two"})
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	cmd.search.output = &output
+	require.NoError(t, cmd.Run(context.Background()))
+
+	var result LogsData
+	require.NoError(t, json.Unmarshal(output.Bytes(), &result))
+	require.Equal(t, []string{"four", "three", "one"}, messages(result.Logs))
+}
+
+func messages(logs []Log) []string {
+	var result []string
+	for _, l := range logs {
+		result = append(result, l.Message)
+	}
+
+	return result
+}
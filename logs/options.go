@@ -2,15 +2,12 @@ package logs
 
 import (
 	"errors"
+	"flag"
 	"fmt"
-	"os"
-	"os/user"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/olebedev/when"
-	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -31,6 +28,7 @@ var (
 	errMinTimeFlag  = errors.New("failed to parse --min-time flag")
 	errMaxTimeFlag  = errors.New("failed to parse --max-time flag")
 	errMissingToken = errors.New("failed to find token")
+	errContextFlag  = errors.New("context flags -A, -B and -C require --grep, --grep-v or --match")
 
 	timeLayouts = []string{
 		time.Layout,
@@ -66,9 +64,44 @@ type Options struct {
 	color      string
 	json       bool
 	version    bool
+	grep       string
+	grepV      string
+	match      matchFlag
+	filters    []logFilter
+	fromFile   string
+
+	after         int
+	before        int
+	contextLines  int
+	contextWindow time.Duration
+
+	outputFile     string
+	outputMaxSize  string
+	outputMaxBytes int64
+	outputBackups  int
+
+	parallel  int
+	RateLimit float64 `yaml:"rate-limit"`
+
+	noCache       bool
+	cacheMaxBytes int64
+	CacheMaxSize  string `yaml:"cache-max-size"`
 
 	ApiUrl string `yaml:"api-url"`
 	Token  string `yaml:"token"`
+
+	Timeout     time.Duration `yaml:"timeout"`
+	DialTimeout time.Duration `yaml:"dial-timeout"`
+	Proxy       string        `yaml:"proxy"`
+	CAFile      string        `yaml:"ca-file"`
+	CertFile    string        `yaml:"cert-file"`
+	KeyFile     string        `yaml:"key-file"`
+	Insecure    bool          `yaml:"insecure"`
+
+	UserAgentSuffix string `yaml:"user-agent-suffix"`
+
+	// flags are the parsed flags, which take precedence over the config file
+	flags *flag.FlagSet
 }
 
 func (opts *Options) Init(args []string) (*Options, error) {
@@ -80,6 +113,48 @@ func (opts *Options) Init(args []string) (*Options, error) {
 		}
 	}
 
+	if _, err := parseQuery(strings.Join(opts.args, " ")); err != nil {
+		return nil, fmt.Errorf("%w: %w", errQuerySyntax, err)
+	}
+
+	filters, err := newLogFilters(opts.grep, opts.grepV, opts.match)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.filters = filters
+
+	if opts.after < 0 || opts.before < 0 || opts.contextLines < 0 || opts.contextWindow < 0 {
+		return nil, fmt.Errorf("%w: values must not be negative", errContextFlag)
+	}
+
+	if opts.contextLines > 0 {
+		if opts.after == 0 {
+			opts.after = opts.contextLines
+		}
+		if opts.before == 0 {
+			opts.before = opts.contextLines
+		}
+	}
+
+	if opts.contextEnabled() {
+		if len(opts.filters) == 0 {
+			return nil, errContextFlag
+		}
+		if opts.contextWindow == 0 {
+			opts.contextWindow = defaultContextWindow
+		}
+	}
+
+	if opts.outputMaxSize != "" {
+		size, err := parseSize(opts.outputMaxSize)
+		if err != nil {
+			return nil, errors.Join(errOutputMaxSizeFlag, err)
+		}
+
+		opts.outputMaxBytes = size
+	}
+
 	if opts.minTime != "" {
 		result, err := parseTime(opts.minTime)
 		if err != nil {
@@ -98,37 +173,28 @@ func (opts *Options) Init(args []string) (*Options, error) {
 		opts.maxTime = result
 	}
 
-	configPath := opts.configFile
-	cwd, err := os.Getwd()
-	if err != nil {
-		return nil, err
+	if opts.parallel < 0 {
+		return nil, fmt.Errorf("%w: value must not be negative", errParallelFlag)
+	}
+	if opts.parallel > 1 && opts.minTime == "" {
+		return nil, fmt.Errorf("%w: splitting the search requires --min-time", errParallelFlag)
 	}
 
-	localConfig := filepath.Join(cwd, ".swo-cli.yaml")
-	if _, err := os.Stat(localConfig); err == nil {
-		configPath = localConfig
-	} else if strings.HasPrefix(opts.configFile, "~/") {
-		usr, err := user.Current()
-		if err != nil {
-			return nil, fmt.Errorf("error while resolving current user to read configuration file: %w", err)
-		}
+	if _, err := opts.loadConfig(); err != nil {
+		return nil, err
+	}
 
-		configPath = filepath.Join(usr.HomeDir, opts.configFile[2:])
+	if opts.Token == "" && !opts.version && opts.fromFile == "" {
+		return nil, errMissingToken
 	}
 
-	if content, err := os.ReadFile(configPath); err == nil {
-		err = yaml.Unmarshal(content, opts)
+	if opts.CacheMaxSize != "" {
+		size, err := parseSize(opts.CacheMaxSize)
 		if err != nil {
-			return nil, fmt.Errorf("error while unmarshaling %s config file: %w", configPath, err)
+			return nil, errors.Join(errCacheMaxSizeFlag, err)
 		}
-	}
 
-	if token := os.Getenv("SWOKEN"); token != "" {
-		opts.Token = token
-	}
-
-	if opts.Token == "" && !opts.version {
-		return nil, errMissingToken
+		opts.cacheMaxBytes = size
 	}
 
 	return opts, nil
@@ -81,6 +81,15 @@ func TestNewOptions(t *testing.T) {
 			expected:      Options{},
 			expectedError: errColorFlag,
 		},
+		{
+			name:          "invalid query",
+			flags:         []string{"--configfile", filepath.Join(os.TempDir(), "config-file.yaml"), "(www", "OR", "db"},
+			expectedError: errQuerySyntax,
+			action: func() {
+				yamlStr := "token: 123456"
+				createConfigFile(t, configFile, yamlStr)
+			},
+		},
 		{
 			name:  "read full config file",
 			flags: []string{"--configfile", filepath.Join(os.TempDir(), "config-file.yaml")},
@@ -95,6 +104,30 @@ func TestNewOptions(t *testing.T) {
 				yamlStr := `
 token: 123456
 api-url: https://api.solarwinds.com
+`
+				createConfigFile(t, configFile, yamlStr)
+			},
+		},
+		{
+			name:  "read connection settings from config file",
+			flags: []string{"--configfile", filepath.Join(os.TempDir(), "config-file.yaml"), "--dial-timeout", "5s"},
+			expected: Options{
+				args:        []string{},
+				count:       defaultCount,
+				configFile:  filepath.Join(os.TempDir(), "config-file.yaml"),
+				ApiUrl:      defaultApiUrl,
+				Token:       "123456",
+				Timeout:     30 * time.Second,
+				DialTimeout: 5 * time.Second,
+				Proxy:       "http://proxy.example.com:3128",
+				Insecure:    true,
+			},
+			action: func() {
+				yamlStr := `
+token: 123456
+timeout: 30s
+proxy: http://proxy.example.com:3128
+insecure: true
 `
 				createConfigFile(t, configFile, yamlStr)
 			},
@@ -198,6 +231,7 @@ api-url: https://api.solarwinds.com
 				return
 			}
 
+			tc.expected.flags = cmd.fs
 			require.Equal(t, &tc.expected, cmd.opts)
 		})
 
@@ -205,6 +239,27 @@ api-url: https://api.solarwinds.com
 	}
 }
 
+func TestFlagsOverrideConfig(t *testing.T) {
+	createConfigFile(t, configFile, `
+token: 123456
+api-url: https://api.eu-01.cloud.solarwinds.com
+timeout: 1m
+insecure: true
+`)
+
+	cmd := NewLogsCommand()
+	require.NoError(t, cmd.Init([]string{"--configfile", configFile}))
+	require.Equal(t, "https://api.eu-01.cloud.solarwinds.com", cmd.opts.ApiUrl)
+	require.Equal(t, time.Minute, cmd.opts.Timeout)
+	require.True(t, cmd.opts.Insecure)
+
+	cmd = NewLogsCommand()
+	require.NoError(t, cmd.Init([]string{"--configfile", configFile, "--timeout", "5s", "--insecure=false", "--api-url", "https://api.example.com"}))
+	require.Equal(t, "https://api.example.com", cmd.opts.ApiUrl)
+	require.Equal(t, 5*time.Second, cmd.opts.Timeout)
+	require.False(t, cmd.opts.Insecure)
+}
+
 func TestParseTime(t *testing.T) {
 	location, err := time.LoadLocation("GMT")
 	require.NoError(t, err)
@@ -0,0 +1,175 @@
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const defaultOutputBackups = 3
+
+var (
+	errOutputMaxSizeFlag = errors.New("failed to parse --output-max-size flag")
+	errInvalidSize       = errors.New("invalid size")
+)
+
+// lineWriter buffers output and flushes it after every complete line, so
+// output stays line-buffered when it is fed into a pipe.
+type lineWriter struct {
+	buf    *bufio.Writer
+	closer io.Closer
+}
+
+// newLineWriter wraps w; closer, when not nil, is closed after the final
+// flush.
+func newLineWriter(w io.Writer, closer io.Closer) *lineWriter {
+	return &lineWriter{buf: bufio.NewWriter(w), closer: closer}
+}
+
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	n, err := lw.buf.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	if bytes.IndexByte(p, '\n') != -1 {
+		return n, lw.buf.Flush()
+	}
+
+	return n, nil
+}
+
+func (lw *lineWriter) Close() error {
+	err := lw.buf.Flush()
+	if lw.closer != nil {
+		err = errors.Join(err, lw.closer.Close())
+	}
+
+	return err
+}
+
+// newOutput returns stdout or the --output-file, both line-buffered.
+func newOutput(opts *Options) (*lineWriter, error) {
+	if opts.outputFile == "" {
+		return newLineWriter(os.Stdout, nil), nil
+	}
+
+	file, err := openRotatingFile(opts.outputFile, opts.outputMaxBytes, opts.outputBackups)
+	if err != nil {
+		return nil, err
+	}
+
+	return newLineWriter(file, file), nil
+}
+
+// rotatingFile appends to path and, once writing would grow it past
+// maxSize, renames it to path.1 (shifting older backups up to path.N) and
+// starts a new file. A maxSize of 0 disables rotation.
+type rotatingFile struct {
+	path    string
+	maxSize int64
+	backups int
+
+	file *os.File
+	size int64
+}
+
+func openRotatingFile(path string, maxSize int64, backups int) (*rotatingFile, error) {
+	if backups <= 0 {
+		backups = defaultOutputBackups
+	}
+
+	r := &rotatingFile{path: path, maxSize: maxSize, backups: backups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("error while opening output file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return errors.Join(err, file.Close())
+	}
+
+	r.file = file
+	r.size = info.Size()
+
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+
+	return n, err
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+
+	for i := r.backups - 1; i > 0; i-- {
+		from := fmt.Sprintf("%s.%d", r.path, i)
+		if _, err := os.Stat(from); err == nil {
+			if err := os.Rename(from, fmt.Sprintf("%s.%d", r.path, i+1)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return err
+	}
+
+	return r.open()
+}
+
+func (r *rotatingFile) Close() error {
+	return r.file.Close()
+}
+
+// parseSize parses sizes like 1048576, 512K, 10M or 1G.
+func parseSize(input string) (int64, error) {
+	multiplier := int64(1)
+	value := strings.TrimSuffix(strings.ToUpper(input), "B")
+	switch {
+	case strings.HasSuffix(value, "K"):
+		multiplier = 1 << 10
+	case strings.HasSuffix(value, "M"):
+		multiplier = 1 << 20
+	case strings.HasSuffix(value, "G"):
+		multiplier = 1 << 30
+	}
+	if multiplier != 1 {
+		value = value[:len(value)-1]
+	}
+
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errors.Join(errInvalidSize, err)
+	}
+	if size < 0 {
+		return 0, fmt.Errorf("%w: size must not be negative", errInvalidSize)
+	}
+
+	return size * multiplier, nil
+}
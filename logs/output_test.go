@@ -0,0 +1,71 @@
+package logs
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := newLineWriter(buf, nil)
+
+	_, err := w.Write([]byte("partial"))
+	require.NoError(t, err)
+	require.Empty(t, buf.String(), "incomplete lines should stay buffered")
+
+	_, err = w.Write([]byte(" line\n"))
+	require.NoError(t, err)
+	require.Equal(t, "partial line\n", buf.String())
+
+	_, err = w.Write([]byte("last"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.Equal(t, "partial line\nlast", buf.String())
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "swo.log")
+
+	f, err := openRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+
+	for _, line := range []string{"one 1234\n", "two 1234\n", "three 12\n", "four 123\n"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, f.Close())
+
+	for name, expected := range map[string]string{
+		"swo.log":   "four 123\n",
+		"swo.log.1": "three 12\n",
+		"swo.log.2": "two 1234\n",
+	} {
+		content, err := os.ReadFile(filepath.Join(filepath.Dir(path), name))
+		require.NoError(t, err)
+		require.Equal(t, expected, string(content), name)
+	}
+
+	_, err = os.Stat(path + ".3")
+	require.True(t, os.IsNotExist(err), "only the configured number of backups should be kept")
+}
+
+func TestParseSize(t *testing.T) {
+	for input, expected := range map[string]int64{
+		"1048576": 1048576,
+		"512K":    512 << 10,
+		"10m":     10 << 20,
+		"1GB":     1 << 30,
+	} {
+		size, err := parseSize(input)
+		require.NoError(t, err)
+		require.Equal(t, expected, size, input)
+	}
+
+	_, err := parseSize("ten megabytes")
+	require.True(t, errors.Is(err, errInvalidSize))
+}
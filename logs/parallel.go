@@ -0,0 +1,171 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	maxParallelWorkers = 8
+	defaultRateLimit   = 5
+)
+
+var errParallelFlag = errors.New("failed to parse --parallel flag")
+
+// rateLimiter spaces requests at least 1/perSecond apart across all the
+// goroutines that share it.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(perSecond float64) *rateLimiter {
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	current := time.Now()
+	if l.next.Before(current) {
+		l.next = current
+	}
+	delay := l.next.Sub(current)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// splitRange splits the time range of q into n adjacent sub-ranges,
+// newest first like the SWO API orders logs.
+func splitRange(q Query, n int) []Query {
+	end := q.EndTime
+	if end.IsZero() {
+		end = now
+	}
+
+	// the API takes whole seconds
+	step := (end.Sub(q.StartTime) / time.Duration(n)).Truncate(time.Second)
+	if step <= 0 {
+		return []Query{q}
+	}
+
+	queries := make([]Query, 0, n)
+	for i := 0; i < n; i++ {
+		sub := q
+		sub.EndTime = end.Add(-time.Duration(i) * step)
+		sub.StartTime = sub.EndTime.Add(-step)
+		if i == n-1 {
+			sub.StartTime = q.StartTime
+		}
+
+		queries = append(queries, sub)
+	}
+
+	return queries
+}
+
+// fetchParallel splits the --min-time/--max-time window into --parallel
+// sub-ranges and fetches them from newest to oldest until they hold --count
+// logs, merged into the newest --count logs of the whole window. The newest
+// sub-range is fetched alone first, as it often holds enough logs already;
+// the others are fetched by a bounded pool of workers.
+func (s *searcher) fetchParallel(ctx context.Context, q Query) (*LogsData, error) {
+	queries := splitRange(q, s.opts.parallel)
+	results := make([]*LogsData, len(queries))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		i    int
+		logs *LogsData
+		err  error
+	}
+
+	// the sub-ranges only share their boundaries, so concatenating them
+	// newest first keeps the order once logs at a boundary are deduplicated
+	var merged LogsData
+	fetched := 0 // sub-ranges merged so far
+	merge := func() {
+		for ; fetched < len(results) && results[fetched] != nil; fetched++ {
+			for _, l := range results[fetched].Logs {
+				if fetched > 0 && !l.Time.Before(queries[fetched].EndTime) && slices.ContainsFunc(results[fetched-1].Logs, func(prev Log) bool {
+					return sameLog(prev, l)
+				}) {
+					continue
+				}
+
+				merged.Logs = append(merged.Logs, l)
+			}
+		}
+	}
+
+	done := make(chan result)
+	next, inFlight, workers := 0, 0, 1
+	var firstErr error
+	for {
+		for firstErr == nil && len(merged.Logs) < s.opts.count && next < len(queries) && inFlight < workers {
+			go func(i int) {
+				logs, err := s.fetchFiltered(ctx, queries[i])
+				done <- result{i: i, logs: logs, err: err}
+			}(next)
+			next++
+			inFlight++
+		}
+		if inFlight == 0 {
+			break
+		}
+
+		r := <-done
+		inFlight--
+		workers = maxParallelWorkers
+		if firstErr != nil || len(merged.Logs) >= s.opts.count {
+			continue
+		}
+		if r.err != nil {
+			firstErr = fmt.Errorf("error while fetching logs from %s to %s: %w", queries[r.i].StartTime.Format(time.RFC3339), queries[r.i].EndTime.Format(time.RFC3339), r.err)
+			cancel()
+			continue
+		}
+
+		results[r.i] = r.logs
+		merge()
+		if len(merged.Logs) >= s.opts.count {
+			// the older sub-ranges still being fetched are not needed
+			cancel()
+		}
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if len(merged.Logs) < s.opts.count {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(merged.Logs) > s.opts.count {
+		merged.Logs = merged.Logs[:s.opts.count]
+	}
+
+	return &merged, nil
+}
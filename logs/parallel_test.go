@@ -0,0 +1,168 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitRange(t *testing.T) {
+	start, err := time.Parse(time.RFC3339, "2000-01-01T00:00:00Z")
+	require.NoError(t, err)
+
+	q := Query{Filter: "error", StartTime: start, EndTime: start.Add(10 * time.Hour)}
+	queries := splitRange(q, 3)
+	require.Len(t, queries, 3)
+
+	require.Equal(t, q.EndTime, queries[0].EndTime)
+	for i := 1; i < len(queries); i++ {
+		require.Equal(t, queries[i-1].StartTime, queries[i].EndTime)
+		require.Equal(t, "error", queries[i].Filter)
+	}
+	require.Equal(t, start, queries[2].StartTime)
+	require.Equal(t, 3*time.Hour+20*time.Minute, queries[0].EndTime.Sub(queries[0].StartTime))
+
+	require.Equal(t, []Query{{StartTime: start, EndTime: start.Add(time.Second)}}, splitRange(Query{StartTime: start, EndTime: start.Add(time.Second)}, 4))
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(100)
+
+	begin := time.Now()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, limiter.wait(context.Background()))
+	}
+	require.True(t, time.Since(begin) >= 40*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	limiter = newRateLimiter(0.001)
+	require.NoError(t, limiter.wait(ctx))
+	require.True(t, errors.Is(limiter.wait(ctx), context.Canceled))
+}
+
+func TestFetchParallel(t *testing.T) {
+	var requests, inFlight, maxInFlight atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/logs", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := maxInFlight.Load()
+			if current <= seen || maxInFlight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		start, err := time.Parse(time.RFC3339, r.URL.Query().Get("startTime"))
+		require.NoError(t, err)
+		end, err := time.Parse(time.RFC3339, r.URL.Query().Get("endTime"))
+		require.NoError(t, err)
+
+		// one log per minute, both ends of the range included
+		var data LogsData
+		for ts := end.Truncate(time.Minute); !ts.Before(start); ts = ts.Add(-time.Minute) {
+			data.Logs = append(data.Logs, Log{Time: ts, Message: ts.Format(time.TimeOnly)})
+		}
+
+		content, err := json.Marshal(data)
+		require.NoError(t, err)
+
+		_, err = w.Write(content)
+		require.NoError(t, err)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	createConfigFile(t, configFile, fmt.Sprintf("token: 1234567\napi-url: %s\nrate-limit: 1000", server.URL))
+
+	cmd := NewLogsCommand()
+	err := cmd.Init([]string{"--configfile", configFile, "--count", "100", "--parallel", "12",
+		"--min-time", "2000-01-01T10:00:00Z", "--max-time", "2000-01-01T11:00:00Z"})
+	require.NoError(t, err)
+
+	q, err := cmd.search.query()
+	require.NoError(t, err)
+
+	logs, err := cmd.search.fetch(context.Background(), q)
+	require.NoError(t, err)
+	require.Equal(t, int32(12), requests.Load())
+	require.True(t, maxInFlight.Load() <= maxParallelWorkers)
+
+	require.Len(t, logs.Logs, 61)
+	for i, l := range logs.Logs {
+		require.Equal(t, q.EndTime.Add(-time.Duration(i)*time.Minute), l.Time)
+	}
+
+	cmd = NewLogsCommand()
+	err = cmd.Init([]string{"--configfile", configFile, "--count", "5", "--parallel", "4",
+		"--min-time", "2000-01-01T10:00:00Z", "--max-time", "2000-01-01T11:00:00Z"})
+	require.NoError(t, err)
+
+	// the newest sub-range holds --count logs, the others are not fetched
+	requests.Store(0)
+	logs, err = cmd.search.fetch(context.Background(), q)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), requests.Load())
+	require.Len(t, logs.Logs, 5)
+	require.Equal(t, q.EndTime, logs.Logs[0].Time)
+
+	// the sub-ranges are fetched from newest to oldest until --count is met
+	cmd = NewLogsCommand()
+	err = cmd.Init([]string{"--configfile", configFile, "--count", "10", "--parallel", "30",
+		"--min-time", "2000-01-01T10:00:00Z", "--max-time", "2000-01-01T11:00:00Z"})
+	require.NoError(t, err)
+
+	requests.Store(0)
+	logs, err = cmd.search.fetch(context.Background(), q)
+	require.NoError(t, err)
+	require.True(t, requests.Load() < 30, "requests: %d", requests.Load())
+	require.Len(t, logs.Logs, 10)
+	for i, l := range logs.Logs {
+		require.Equal(t, q.EndTime.Add(-time.Duration(i)*time.Minute), l.Time)
+	}
+}
+
+func TestFetchParallelError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/logs", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	createConfigFile(t, configFile, fmt.Sprintf("token: 1234567\napi-url: %s\nrate-limit: 1000", server.URL))
+
+	cmd := NewLogsCommand()
+	err := cmd.Init([]string{"--configfile", configFile, "--parallel", "4", "--min-time", "2000-01-01T10:00:00Z"})
+	require.NoError(t, err)
+
+	q, err := cmd.search.query()
+	require.NoError(t, err)
+
+	_, err = cmd.search.fetch(context.Background(), q)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "received 429 status code")
+}
+
+func TestParallelFlag(t *testing.T) {
+	createConfigFile(t, configFile, "token: 1234567")
+
+	cmd := NewLogsCommand()
+	err := cmd.Init([]string{"--configfile", configFile, "--parallel", "4"})
+	require.True(t, errors.Is(err, errParallelFlag), "error: %v", err)
+
+	cmd = NewLogsCommand()
+	err = cmd.Init([]string{"--configfile", configFile, "--parallel", "-1"})
+	require.True(t, errors.Is(err, errParallelFlag), "error: %v", err)
+}
@@ -0,0 +1,354 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var errQuerySyntax = errors.New("invalid search query")
+
+type queryNodeKind int
+
+const (
+	termNode queryNodeKind = iota
+	phraseNode
+	fieldNode
+	notNode
+	andNode
+	orNode
+)
+
+type queryNode struct {
+	kind     queryNodeKind
+	field    string
+	value    string
+	children []*queryNode
+}
+
+type QueryError struct {
+	Query string
+	Pos   int
+	Msg   string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos+1)
+}
+
+// Pointer renders the query with a caret under the offending position.
+func (e *QueryError) Pointer() string {
+	return fmt.Sprintf("  %s\n  %s^", e.Query, strings.Repeat(" ", e.Pos))
+}
+
+type queryTokenKind int
+
+const (
+	wordToken queryTokenKind = iota
+	phraseToken
+	lparenToken
+	rparenToken
+	andToken
+	orToken
+	notToken
+	endToken
+)
+
+type queryToken struct {
+	kind  queryTokenKind
+	value string
+	pos   int
+}
+
+func tokenizeQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	i := 0
+	for i < len(input) {
+		ch := input[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			i++
+		case ch == '(':
+			tokens = append(tokens, queryToken{kind: lparenToken, value: "(", pos: i})
+			i++
+		case ch == ')':
+			tokens = append(tokens, queryToken{kind: rparenToken, value: ")", pos: i})
+			i++
+		case ch == '"':
+			end := strings.IndexByte(input[i+1:], '"')
+			if end == -1 {
+				return nil, &QueryError{Query: input, Pos: i, Msg: "unterminated quoted phrase"}
+			}
+
+			tokens = append(tokens, queryToken{kind: phraseToken, value: input[i+1 : i+1+end], pos: i})
+			i += end + 2
+		case ch == '-':
+			tokens = append(tokens, queryToken{kind: notToken, value: "-", pos: i})
+			i++
+		default:
+			start := i
+			for i < len(input) && !strings.ContainsRune(" \t\n()\"", rune(input[i])) {
+				// field:"quoted value" keeps the phrase as part of the word
+				if input[i] == ':' && i+1 < len(input) && input[i+1] == '"' {
+					end := strings.IndexByte(input[i+2:], '"')
+					if end == -1 {
+						return nil, &QueryError{Query: input, Pos: i + 1, Msg: "unterminated quoted phrase"}
+					}
+					i += end + 3
+					break
+				}
+				i++
+			}
+
+			word := input[start:i]
+			switch word {
+			case "AND":
+				tokens = append(tokens, queryToken{kind: andToken, value: word, pos: start})
+			case "OR":
+				tokens = append(tokens, queryToken{kind: orToken, value: word, pos: start})
+			default:
+				tokens = append(tokens, queryToken{kind: wordToken, value: word, pos: start})
+			}
+		}
+	}
+
+	return append(tokens, queryToken{kind: endToken, pos: len(input)}), nil
+}
+
+type queryParser struct {
+	input  string
+	tokens []queryToken
+	pos    int
+	depth  int
+}
+
+// parseQuery parses the boolean search syntax supported by SWO: implicit AND
+// between terms, AND/OR operators, "-" negation, parentheses, quoted phrases
+// and field:value pairs. An empty query yields a nil node.
+func parseQuery(input string) (*queryNode, error) {
+	tokens, err := tokenizeQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &queryParser{input: input, tokens: tokens}
+	if p.peek().kind == endToken {
+		return nil, nil
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != endToken {
+		if tok.kind == rparenToken {
+			return nil, p.errorAt(tok, "unbalanced closing parenthesis")
+		}
+
+		return nil, p.errorAt(tok, fmt.Sprintf("unexpected %q", tok.value))
+	}
+
+	return node, nil
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() queryToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != endToken {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *queryParser) errorAt(tok queryToken, msg string) error {
+	return &QueryError{Query: p.input, Pos: tok.pos, Msg: msg}
+}
+
+func (p *queryParser) parseOr() (*queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	node := left
+	for p.peek().kind == orToken {
+		op := p.next()
+		if !p.startsOperand() {
+			return nil, p.errorAt(op, "missing right operand for OR")
+		}
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		if node.kind == orNode {
+			node.children = append(node.children, right)
+		} else {
+			node = &queryNode{kind: orNode, children: []*queryNode{node, right}}
+		}
+	}
+
+	return node, nil
+}
+
+func (p *queryParser) parseAnd() (*queryNode, error) {
+	if tok := p.peek(); !p.startsOperand() {
+		return nil, p.unexpected(tok)
+	}
+
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	node := left
+	for {
+		tok := p.peek()
+		if tok.kind == andToken {
+			p.next()
+			if !p.startsOperand() {
+				return nil, p.errorAt(tok, "missing right operand for AND")
+			}
+		} else if !p.startsOperand() {
+			return node, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		if node.kind == andNode {
+			node.children = append(node.children, right)
+		} else {
+			node = &queryNode{kind: andNode, children: []*queryNode{node, right}}
+		}
+	}
+}
+
+func (p *queryParser) parseUnary() (*queryNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case notToken:
+		if !p.startsOperand() || p.peek().pos != tok.pos+1 {
+			return nil, p.errorAt(tok, "nothing to negate")
+		}
+
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &queryNode{kind: notNode, children: []*queryNode{child}}, nil
+	case lparenToken:
+		p.depth++
+		if p.peek().kind == rparenToken {
+			return nil, p.errorAt(p.peek(), "empty parentheses")
+		}
+
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek().kind != rparenToken {
+			return nil, p.errorAt(tok, "unbalanced opening parenthesis")
+		}
+		p.next()
+		p.depth--
+
+		return node, nil
+	case phraseToken:
+		return &queryNode{kind: phraseNode, value: tok.value}, nil
+	case wordToken:
+		if field, value, ok := strings.Cut(tok.value, ":"); ok && field != "" && value != "" {
+			return &queryNode{kind: fieldNode, field: field, value: strings.Trim(value, `"`)}, nil
+		}
+
+		return &queryNode{kind: termNode, value: tok.value}, nil
+	default:
+		return nil, p.unexpected(tok)
+	}
+}
+
+func (p *queryParser) startsOperand() bool {
+	switch p.peek().kind {
+	case wordToken, phraseToken, lparenToken, notToken:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *queryParser) unexpected(tok queryToken) error {
+	switch tok.kind {
+	case rparenToken:
+		if p.depth == 0 {
+			return p.errorAt(tok, "unbalanced closing parenthesis")
+		}
+
+		return p.errorAt(tok, "missing search term before closing parenthesis")
+	case endToken:
+		return p.errorAt(tok, "unexpected end of query")
+	default:
+		return p.errorAt(tok, fmt.Sprintf("unexpected %q", tok.value))
+	}
+}
+
+// match evaluates the query against a single log the way the SWO search
+// does for the supported syntax: terms and phrases are case-insensitive
+// substrings of the hostname, program or message, field:value compares a
+// whole field, or is a term when there is no such field, and a trailing "*"
+// turns either into a prefix match.
+func (n *queryNode) match(l Log) bool {
+	switch n.kind {
+	case termNode, phraseNode:
+		return containsFold(l.Hostname, n.value) || containsFold(l.Program, n.value) || containsFold(l.Message, n.value)
+	case fieldNode:
+		field, ok := logFields[strings.ToLower(n.field)]
+		if !ok {
+			// not a field of the log, e.g. user:alice or 10:00:30, so the
+			// whole word is searched like a term
+			term := n.field + ":" + n.value
+			return containsFold(l.Hostname, term) || containsFold(l.Program, term) || containsFold(l.Message, term)
+		}
+
+		value := field(l)
+		if prefix, ok := strings.CutSuffix(n.value, "*"); ok {
+			return len(value) >= len(prefix) && strings.EqualFold(value[:len(prefix)], prefix)
+		}
+
+		return strings.EqualFold(value, n.value)
+	case notNode:
+		return !n.children[0].match(l)
+	case andNode:
+		for _, child := range n.children {
+			if !child.match(l) {
+				return false
+			}
+		}
+
+		return true
+	case orNode:
+		for _, child := range n.children {
+			if child.match(l) {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return false
+	}
+}
+
+func containsFold(s, substr string) bool {
+	substr = strings.TrimSuffix(substr, "*")
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,138 @@
+package logs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected *queryNode
+	}{
+		{
+			name:  "empty query",
+			input: "",
+		},
+		{
+			name:     "single term",
+			input:    "something",
+			expected: &queryNode{kind: termNode, value: "something"},
+		},
+		{
+			name:  "implicit and",
+			input: `1.2.3 Failure`,
+			expected: &queryNode{kind: andNode, children: []*queryNode{
+				{kind: termNode, value: "1.2.3"},
+				{kind: termNode, value: "Failure"},
+			}},
+		},
+		{
+			name:  "groups, or and negation",
+			input: "(www OR db) (nginx OR pgsql) -accepted",
+			expected: &queryNode{kind: andNode, children: []*queryNode{
+				{kind: orNode, children: []*queryNode{
+					{kind: termNode, value: "www"},
+					{kind: termNode, value: "db"},
+				}},
+				{kind: orNode, children: []*queryNode{
+					{kind: termNode, value: "nginx"},
+					{kind: termNode, value: "pgsql"},
+				}},
+				{kind: notNode, children: []*queryNode{
+					{kind: termNode, value: "accepted"},
+				}},
+			}},
+		},
+		{
+			name:  "phrase and field",
+			input: `host:ns1 AND "connection refused" program:"my app"`,
+			expected: &queryNode{kind: andNode, children: []*queryNode{
+				{kind: fieldNode, field: "host", value: "ns1"},
+				{kind: phraseNode, value: "connection refused"},
+				{kind: fieldNode, field: "program", value: "my app"},
+			}},
+		},
+		{
+			name:     "negation only",
+			input:    "-redis",
+			expected: &queryNode{kind: notNode, children: []*queryNode{{kind: termNode, value: "redis"}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			node, err := parseQuery(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, node)
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		expectedPos int
+		expectedMsg string
+	}{
+		{
+			name:        "unbalanced opening parenthesis",
+			input:       "(www OR db nginx",
+			expectedPos: 0,
+			expectedMsg: "unbalanced opening parenthesis",
+		},
+		{
+			name:        "unbalanced closing parenthesis",
+			input:       "www OR db) nginx",
+			expectedPos: 9,
+			expectedMsg: "unbalanced closing parenthesis",
+		},
+		{
+			name:        "unterminated phrase",
+			input:       `error "connection reset`,
+			expectedPos: 6,
+			expectedMsg: "unterminated quoted phrase",
+		},
+		{
+			name:        "dangling or",
+			input:       "www OR",
+			expectedPos: 4,
+			expectedMsg: "missing right operand for OR",
+		},
+		{
+			name:        "empty parentheses",
+			input:       "www ()",
+			expectedPos: 5,
+			expectedMsg: "empty parentheses",
+		},
+		{
+			name:        "lone negation",
+			input:       "www - db",
+			expectedPos: 4,
+			expectedMsg: "nothing to negate",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseQuery(tc.input)
+
+			var queryErr *QueryError
+			require.True(t, errors.As(err, &queryErr), "error: %v", err)
+			require.Equal(t, tc.expectedPos, queryErr.Pos)
+			require.Equal(t, tc.expectedMsg, queryErr.Msg)
+		})
+	}
+}
+
+func TestQueryErrorPointer(t *testing.T) {
+	_, err := parseQuery("(www OR db")
+
+	var queryErr *QueryError
+	require.True(t, errors.As(err, &queryErr))
+	require.Equal(t, "  (www OR db\n  ^", queryErr.Pointer())
+}
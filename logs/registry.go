@@ -0,0 +1,317 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// A mistyped command name is suggested when it is at most
+// maxSuggestionDistance edits away from a known name of at least
+// minSuggestionLength characters. Shorter names are too close to common
+// words, e.g. host and hist, that are searched for instead.
+const (
+	maxSuggestionDistance = 1
+	minSuggestionLength   = 5
+)
+
+const (
+	textOutput = "text"
+	jsonOutput = "json"
+)
+
+var (
+	ErrNoCommand = errors.New("no command given")
+
+	errUnknownCommand    = errors.New("unknown command")
+	errMissingSubcommand = errors.New("missing subcommand")
+	errOutputFlag        = errors.New("failed to parse --output flag")
+)
+
+// globals holds the flags that every command accepts, before or after its
+// name.
+var globals globalOptions
+
+type globalOptions struct {
+	profile string
+	debug   bool
+	output  string
+}
+
+func registerGlobalFlags(h *commandHelp) {
+	h.stringVar(&globals.profile, "profile", "", "NAME", "Use the settings of profile NAME of the config file").global = true
+	h.boolVar(&globals.debug, "debug", "Log debug information, e.g. response sizes").global = true
+	h.stringVar(&globals.output, "output", "", "FORMAT", "Output format of commands with JSON output: text or json").global = true
+}
+
+// applyOutputFormat turns on the --json flag of the command for --output
+// json.
+func (h *commandHelp) applyOutputFormat() error {
+	switch globals.output {
+	case "", textOutput:
+		return nil
+	case jsonOutput:
+		if h.fs.Lookup("json") == nil {
+			return fmt.Errorf("%w: %s has no JSON output", errOutputFlag, h.fs.Name())
+		}
+
+		return h.fs.Set("json", "true")
+	default:
+		return fmt.Errorf("%w: expected text or json, got %q", errOutputFlag, globals.output)
+	}
+}
+
+// Command is a command run by the Registry.
+type Command interface {
+	Init(args []string) error
+	Run(ctx context.Context) error
+	Name() string
+	Help() *commandHelp
+}
+
+// Registry finds the command named by the arguments. Command names may have
+// two words, e.g. "cache clear", which groups commands under their first
+// word.
+type Registry struct {
+	help     *commandHelp
+	commands []Command
+	aliases  map[string]string
+	fallback Command
+}
+
+func NewRegistry() *Registry {
+	r := &Registry{
+		help:    newCommandHelp(cliName, "", "[global flags] <command> [flags] [arguments]"),
+		aliases: map[string]string{},
+	}
+
+	// Errors are reported by the fallback command, which also accepts the
+	// global flags.
+	r.help.fs.SetOutput(io.Discard)
+	r.help.fs.Usage = func() {}
+
+	return r
+}
+
+// Register adds cmd, which can also be run by any of aliases.
+func (r *Registry) Register(cmd Command, aliases ...string) {
+	r.commands = append(r.commands, cmd)
+	for _, alias := range aliases {
+		r.aliases[alias] = cmd.Name()
+	}
+}
+
+// SetDefault makes cmd run when the arguments do not start with a command
+// name, so that "swo-cli error" searches like "swo-cli logs error".
+func (r *Registry) SetDefault(cmd Command) {
+	r.fallback = cmd
+}
+
+func (r *Registry) lookup(name string) Command {
+	if alias, ok := r.aliases[name]; ok {
+		name = alias
+	}
+
+	i := slices.IndexFunc(r.commands, func(cmd Command) bool { return cmd.Name() == name })
+	if i < 0 {
+		return nil
+	}
+
+	return r.commands[i]
+}
+
+// aliasesOf returns the aliases of the command called name.
+func (r *Registry) aliasesOf(name string) []string {
+	var aliases []string
+	for _, alias := range sortedKeys(r.aliases) {
+		if r.aliases[alias] == name {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	return aliases
+}
+
+// subcommands returns the commands grouped under group.
+func (r *Registry) subcommands(group string) []Command {
+	var cmds []Command
+	for _, cmd := range r.commands {
+		if strings.HasPrefix(cmd.Name(), group+" ") {
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	return cmds
+}
+
+// Init parses the global flags, finds the command named by the following
+// arguments and initializes it with the rest. The command is returned
+// even if its initialization fails.
+func (r *Registry) Init(args []string) (Command, error) {
+	cmd, args, err := r.parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cmd.Init(args)
+	if globals.debug {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+	}
+
+	return cmd, err
+}
+
+// parse returns the command and its arguments. No arguments, and arguments
+// that begin with a flag other than a global one or with "--", are passed
+// to the default command.
+func (r *Registry) parse(args []string) (Command, []string, error) {
+	err := r.help.fs.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		r.WriteOverview(os.Stdout)
+		return nil, nil, err
+	}
+	if err != nil {
+		if r.fallback == nil {
+			return nil, nil, err
+		}
+
+		return r.fallback, args, nil
+	}
+
+	rest := r.help.fs.Args()
+	if r.fallback != nil && len(rest) == 0 {
+		return r.fallback, args, nil
+	}
+	if parsed := len(args) - len(rest); r.fallback != nil && parsed > 0 && args[parsed-1] == "--" {
+		return r.fallback, append([]string{"--"}, rest...), nil
+	}
+
+	return r.find(rest)
+}
+
+func (r *Registry) find(args []string) (Command, []string, error) {
+	if len(args) == 0 {
+		return nil, nil, ErrNoCommand
+	}
+
+	for n := min(len(args), 2); n > 0; n-- {
+		if cmd := r.lookup(strings.Join(args[:n], " ")); cmd != nil {
+			return cmd, args[n:], nil
+		}
+	}
+
+	name := args[0]
+	subcommands := r.subcommands(name)
+	if len(subcommands) > 0 && len(args) > 1 && !strings.HasPrefix(args[1], "-") {
+		name += " " + args[1]
+	}
+
+	// A query term that differs from a long command name by a single edit
+	// is more likely a typo of it, and is not searched for.
+	if suggestion := r.suggest(name); suggestion != "" {
+		return nil, nil, fmt.Errorf("%w %q, did you mean %s?", errUnknownCommand, name, suggestion)
+	}
+
+	if r.fallback != nil {
+		return r.fallback, args, nil
+	}
+
+	if len(subcommands) > 0 && name == args[0] {
+		var names []string
+		for _, cmd := range subcommands {
+			names = append(names, cmd.Name())
+		}
+
+		return nil, nil, fmt.Errorf("%w of %s, expected %s", errMissingSubcommand, name, strings.Join(names, " or "))
+	}
+
+	return nil, nil, fmt.Errorf("%w %q", errUnknownCommand, name)
+}
+
+// suggest returns the command name or alias closest to name, if it is
+// close enough to be a typo.
+func (r *Registry) suggest(name string) string {
+	candidates := sortedKeys(r.aliases)
+	for _, cmd := range r.commands {
+		candidates = append(candidates, cmd.Name())
+	}
+
+	suggestion, best := "", maxSuggestionDistance+1
+	for _, candidate := range candidates {
+		if utf8.RuneCountInString(candidate) < minSuggestionLength {
+			continue
+		}
+		if distance := editDistance(name, candidate); distance < best {
+			suggestion, best = candidate, distance
+		}
+	}
+
+	return suggestion
+}
+
+// editDistance returns the number of insertions, deletions, substitutions
+// and transpositions of adjacent characters that turn a into b.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	var beforePrevious []int
+	previous := make([]int, len(t)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := range s {
+		current := make([]int, len(t)+1)
+		current[0] = i + 1
+		for j := range t {
+			cost := 1
+			if s[i] == t[j] {
+				cost = 0
+			}
+
+			current[j+1] = min(previous[j+1]+1, current[j]+1, previous[j]+cost)
+			if i > 0 && j > 0 && s[i] == t[j-1] && s[i-1] == t[j] {
+				current[j+1] = min(current[j+1], beforePrevious[j-1]+1)
+			}
+		}
+
+		beforePrevious, previous = previous, current
+	}
+
+	return previous[len(t)]
+}
+
+// WriteOverview lists the commands with their aliases and summaries, and
+// the global flags.
+func (r *Registry) WriteOverview(w io.Writer) error {
+	names := map[Command]string{}
+	width := 0
+	for _, cmd := range r.commands {
+		names[cmd] = strings.Join(append([]string{cmd.Name()}, r.aliasesOf(cmd.Name())...), ", ")
+		width = max(width, len(names[cmd]))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\nUsage: %s\n\n", r.help.synopsis())
+	fmt.Fprintf(&b, "Commands:\n")
+	for _, cmd := range r.commands {
+		fmt.Fprintf(&b, "  %-*s  %s\n", width, names[cmd], cmd.Help().summary)
+	}
+
+	fmt.Fprintf(&b, "\nGlobal flags:\n")
+	r.help.writeFlags(&b, r.help.globalFlags(), "  ")
+
+	if r.fallback != nil {
+		fmt.Fprintf(&b, "\nArguments that do not start with a command are passed to %s, e.g. '%s -s web error'.\n", r.fallback.Name(), cliName)
+	}
+	fmt.Fprintf(&b, "\nRun '%s help <command>' for the flags and examples of a command.\n", cliName)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
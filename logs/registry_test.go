@@ -0,0 +1,241 @@
+package logs
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testRegistry() *Registry {
+	registry := NewRegistry()
+	logs := NewLogsCommand()
+	registry.Register(logs)
+	registry.SetDefault(logs)
+	registry.Register(NewStatsCommand())
+	registry.Register(NewHistogramCommand(), "hist")
+	registry.Register(NewExploreCommand())
+	registry.Register(NewEntitiesCommand())
+	registry.Register(NewGroupsCommand())
+	registry.Register(NewCacheCommand())
+	registry.Register(NewConfigShowCommand())
+	registry.Register(NewHelpCommand(registry))
+	registry.Register(NewCompletionCommand(registry))
+
+	return registry
+}
+
+func TestRegistryParse(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expected      string
+		rest          []string
+		expectedError error
+		message       string
+	}{
+		{
+			name:     "command",
+			args:     []string{"logs", "-j", "error"},
+			expected: logsCommandName,
+			rest:     []string{"-j", "error"},
+		},
+		{
+			name:     "alias",
+			args:     []string{"hist", "--by-severity"},
+			expected: histogramCommandName,
+			rest:     []string{"--by-severity"},
+		},
+		{
+			name:     "subcommand",
+			args:     []string{"cache", "clear"},
+			expected: cacheClearCommandName,
+			rest:     []string{},
+		},
+		{
+			name:     "no arguments",
+			args:     []string{},
+			expected: logsCommandName,
+			rest:     []string{},
+		},
+		{
+			name:     "group name without subcommand",
+			args:     []string{"cache"},
+			expected: logsCommandName,
+			rest:     []string{"cache"},
+		},
+		{
+			name:     "group name before flags",
+			args:     []string{"config", "-j"},
+			expected: logsCommandName,
+			rest:     []string{"config", "-j"},
+		},
+		{
+			name:          "typo",
+			args:          []string{"stast", "error"},
+			expectedError: errUnknownCommand,
+			message:       `unknown command "stast", did you mean stats?`,
+		},
+		{
+			name:          "typo of a subcommand",
+			args:          []string{"cache", "claer"},
+			expectedError: errUnknownCommand,
+			message:       `unknown command "cache claer", did you mean cache clear?`,
+		},
+		{
+			name:     "unknown subcommand",
+			args:     []string{"cache", "error"},
+			expected: logsCommandName,
+			rest:     []string{"cache", "error"},
+		},
+		{
+			name:     "words close to short command names",
+			args:     []string{"host", "www42", "post", "500", "list", "users", "hits", "log"},
+			expected: logsCommandName,
+			rest:     []string{"host", "www42", "post", "500", "list", "users", "hits", "log"},
+		},
+		{
+			name:     "word two edits from a command name",
+			args:     []string{"start", "counts"},
+			expected: logsCommandName,
+			rest:     []string{"start", "counts"},
+		},
+		{
+			name:     "query",
+			args:     []string{"connection", "refused", "-j"},
+			expected: logsCommandName,
+			rest:     []string{"connection", "refused", "-j"},
+		},
+		{
+			name:     "query after global flags",
+			args:     []string{"--profile", "eu", "error"},
+			expected: logsCommandName,
+			rest:     []string{"error"},
+		},
+		{
+			name:     "flags before the query",
+			args:     []string{"-s", "web", "--json", "error"},
+			expected: logsCommandName,
+			rest:     []string{"-s", "web", "--json", "error"},
+		},
+		{
+			name:     "double dash",
+			args:     []string{"--", "-redis"},
+			expected: logsCommandName,
+			rest:     []string{"--", "-redis"},
+		},
+		{
+			name:     "command name after double dash",
+			args:     []string{"--", "stats"},
+			expected: logsCommandName,
+			rest:     []string{"--", "stats"},
+		},
+	}
+
+	defer func() { globals = globalOptions{} }()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, rest, err := testRegistry().parse(tc.args)
+			require.True(t, errors.Is(err, tc.expectedError), "error: %v, expected: %v", err, tc.expectedError)
+			if tc.expectedError != nil {
+				if tc.message != "" {
+					require.EqualError(t, err, tc.message)
+				}
+				return
+			}
+
+			require.Equal(t, tc.expected, cmd.Name())
+			require.Equal(t, tc.rest, rest)
+		})
+	}
+}
+
+func TestRegistryWithoutDefault(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewLogsCommand())
+	registry.Register(NewCacheCommand())
+
+	_, _, err := registry.find([]string{"cache"})
+	require.EqualError(t, err, "missing subcommand of cache, expected cache clear")
+
+	_, _, err = registry.find([]string{"cache", "error"})
+	require.EqualError(t, err, `unknown command "cache error"`)
+
+	_, _, err = registry.find([]string{"error"})
+	require.EqualError(t, err, `unknown command "error"`)
+}
+
+func TestRegistryGlobalFlags(t *testing.T) {
+	defer func() { globals = globalOptions{} }()
+
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config.yaml")
+	createConfigFile(t, config, `
+token: 123456
+profiles:
+  eu:
+    token: 654321
+    api-url: https://api.eu-01.cloud.solarwinds.com
+`)
+
+	registry := testRegistry()
+	cmd, err := registry.Init([]string{"--profile", "eu", "config", "show", "-c", config})
+	require.NoError(t, err)
+	require.Equal(t, configShowCommandName, cmd.Name())
+	require.Equal(t, "eu", globals.profile)
+
+	show := cmd.(*configShowCommand)
+	require.Equal(t, "654321", show.opts.Token)
+	require.Equal(t, "https://api.eu-01.cloud.solarwinds.com", show.opts.ApiUrl)
+
+	show = NewConfigShowCommand()
+	globals.profile = "us"
+	err = show.Init([]string{"-c", config})
+	require.True(t, errors.Is(err, errUnknownProfile), "error: %v", err)
+}
+
+func TestRegistryOutputFormat(t *testing.T) {
+	defer func() { globals = globalOptions{} }()
+
+	createConfigFile(t, configFile, "token: 1234567")
+
+	cmd, err := testRegistry().Init([]string{"--output", "json", "stats", "-c", configFile})
+	require.NoError(t, err)
+	require.True(t, cmd.(*statsCommand).opts.json)
+
+	globals = globalOptions{}
+	cmd, err = testRegistry().Init([]string{"stats", "-c", configFile, "--output", "text"})
+	require.NoError(t, err)
+	require.False(t, cmd.(*statsCommand).opts.json)
+
+	globals = globalOptions{}
+	_, err = testRegistry().Init([]string{"--output", "json", "config", "show", "-c", configFile})
+	require.EqualError(t, err, "failed to parse --output flag: config show has no JSON output")
+
+	globals = globalOptions{}
+	_, err = testRegistry().Init([]string{"--output", "out.txt", "stats", "-c", configFile})
+	require.True(t, errors.Is(err, errOutputFlag), "error: %v", err)
+}
+
+func TestRegistryNoCommand(t *testing.T) {
+	defer func() { globals = globalOptions{} }()
+
+	registry := NewRegistry()
+	registry.Register(NewStatsCommand())
+
+	_, _, err := registry.parse([]string{"--debug"})
+	require.True(t, errors.Is(err, ErrNoCommand), "error: %v", err)
+
+	_, _, err = registry.parse([]string{"error"})
+	require.EqualError(t, err, `unknown command "error"`)
+}
+
+func TestEditDistance(t *testing.T) {
+	require.Equal(t, 0, editDistance("logs", "logs"))
+	require.Equal(t, 1, editDistance("log", "logs"))
+	require.Equal(t, 1, editDistance("stast", "stats"))
+	require.Equal(t, 1, editDistance("host", "hist"))
+	require.Equal(t, 3, editDistance("", "abc"))
+}
@@ -0,0 +1,327 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jskiba/papertrail-cli-poc/version"
+)
+
+type ColorStrFunc func(format string, a ...interface{}) string
+
+var colors = []ColorStrFunc{
+	color.CyanString,
+	color.YellowString,
+	color.GreenString,
+	color.MagentaString,
+	color.RedString,
+}
+
+// searcher runs the CLI commands on top of Client: it turns Options into
+// queries, applies local filters and prints the results.
+type searcher struct {
+	client *Client
+	opts   *Options
+	output io.Writer
+
+	// limiter is shared by all requests of the searcher, nil when there is
+	// no rate limit
+	limiter *rateLimiter
+
+	// cache is nil with --no-cache
+	cache *resultCache
+
+	// offline answers all queries when --from-file is set
+	offline *offlineSource
+
+	hostnameColorIdx int
+	programColorIdx  int
+}
+
+// newAPIClient returns a Client configured by the connection flags.
+func newAPIClient(opts *Options) (*Client, error) {
+	httpClient, err := newHTTPClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	userAgent := DefaultUserAgent()
+	if opts.UserAgentSuffix != "" {
+		userAgent = userAgent + " " + opts.UserAgentSuffix
+	}
+
+	return NewClient(
+		WithApiUrl(opts.ApiUrl),
+		WithToken(opts.Token),
+		WithHttpClient(httpClient),
+		WithUserAgent(userAgent),
+	)
+}
+
+func newSearcher(opts *Options) (*searcher, error) {
+	client, err := newAPIClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := newOutput(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var offline *offlineSource
+	if opts.fromFile != "" {
+		offline, err = newOfflineSource(opts.fromFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var limiter *rateLimiter
+	switch {
+	case opts.RateLimit > 0:
+		limiter = newRateLimiter(opts.RateLimit)
+	case opts.parallel > 1:
+		limiter = newRateLimiter(defaultRateLimit)
+	}
+
+	var cache *resultCache
+	if !opts.noCache && offline == nil {
+		cache, err = newResultCache(opts.cacheMaxBytes)
+		if err != nil {
+			slog.Warn("Caching is disabled", slog.String("error", err.Error()))
+		}
+	}
+
+	hostnameColorIdx, programColorIdx := pickColors(opts.color)
+
+	return &searcher{
+		client:           client,
+		opts:             opts,
+		output:           output,
+		limiter:          limiter,
+		cache:            cache,
+		offline:          offline,
+		hostnameColorIdx: hostnameColorIdx,
+		programColorIdx:  programColorIdx,
+	}, nil
+}
+
+// pickColors chooses the colors once per searcher so that every printed
+// batch of logs uses the same ones.
+func pickColors(colorFlag string) (int, int) {
+	hostnameColorIdx := -1
+	programColorIdx := -1
+	switch colorFlag {
+	case system:
+		hostnameColorIdx = rand.IntN(len(colors))
+	case program:
+		programColorIdx = rand.IntN(len(colors))
+	case all:
+		programColorIdx = rand.IntN(len(colors))
+		hostnameColorIdx = rand.IntN(len(colors))
+		for hostnameColorIdx == programColorIdx {
+			hostnameColorIdx = rand.IntN(len(colors))
+		}
+	default:
+	}
+
+	return hostnameColorIdx, programColorIdx
+}
+
+// query builds the first page query from the command line flags.
+func (s *searcher) query() (Query, error) {
+	q := Query{
+		Group:    s.opts.group,
+		PageSize: s.opts.count,
+	}
+
+	if s.opts.minTime != "" {
+		startTime, err := time.Parse(time.RFC3339, s.opts.minTime)
+		if err != nil {
+			return q, err
+		}
+
+		q.StartTime = startTime
+	}
+
+	if s.opts.maxTime != "" {
+		endTime, err := time.Parse(time.RFC3339, s.opts.maxTime)
+		if err != nil {
+			return q, err
+		}
+
+		q.EndTime = endTime
+	}
+
+	var filter string
+	if s.opts.system != "" {
+		filter = fmt.Sprintf("host:%s", s.opts.system)
+	}
+	if len(s.opts.args) != 0 {
+		if len(filter) == 0 {
+			filter = strings.Join(s.opts.args, " ")
+		} else {
+			filter = filter + " " + strings.Join(s.opts.args, " ")
+		}
+	}
+
+	q.Filter = filter
+
+	return q, nil
+}
+
+func (s *searcher) printResult(logs *LogsData) error {
+	if s.opts.json {
+		jsonFormat, err := json.Marshal(logs)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(s.output, string(jsonFormat))
+		return err
+	}
+
+	for i := len(logs.Logs) - 1; i >= 0; i-- {
+		l := logs.Logs[i]
+		hostname := l.Hostname
+		program := l.Program
+		if s.hostnameColorIdx != -1 {
+			hostname = colors[s.hostnameColorIdx](hostname)
+		}
+		if s.programColorIdx != -1 {
+			program = colors[s.programColorIdx](program)
+		}
+
+		_, err := fmt.Fprintf(s.output, "%s %s %s %s\n", l.Time.Format("Jan 02 15:04:05"), hostname, program, l.Message)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchPage fetches a single page from the --from-file logs, the cache or,
+// once the rate limit allows it, from SWO.
+func (s *searcher) fetchPage(ctx context.Context, q Query) (*LogsData, error) {
+	if s.offline != nil {
+		return s.offline.search(q)
+	}
+
+	var cachePath string
+	if s.cache != nil && cacheable(q) {
+		path, err := s.cache.path(s.opts.ApiUrl, s.opts.Token, q)
+		if err != nil {
+			return nil, err
+		}
+
+		if logs, ok := s.cache.get(path); ok {
+			slog.Debug("Using cached SWO response", slog.String("path", path))
+			return logs, nil
+		}
+
+		cachePath = path
+	}
+
+	if s.limiter != nil {
+		if err := s.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	logs, err := s.client.Search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if cachePath != "" {
+		if err := s.cache.put(cachePath, logs); err != nil {
+			slog.Warn("Could not cache SWO response", slog.String("error", err.Error()))
+		}
+	}
+
+	return logs, nil
+}
+
+// fetch returns the newest --count logs that pass the local filters.
+func (s *searcher) fetch(ctx context.Context, q Query) (*LogsData, error) {
+	if s.opts.parallel > 1 {
+		return s.fetchParallel(ctx, q)
+	}
+
+	return s.fetchFiltered(ctx, q)
+}
+
+// fetchFiltered walks pages until --count logs pass the local filters or
+// there are no more pages to fetch. Without filters every log passes, so
+// pages shorter than --count are followed as well.
+func (s *searcher) fetchFiltered(ctx context.Context, q Query) (*LogsData, error) {
+	var result LogsData
+	for {
+		logs, err := s.fetchPage(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+
+		result.PageInfo = logs.PageInfo
+		for _, l := range logs.Logs {
+			if matchesFilters(s.opts.filters, l) {
+				result.Logs = append(result.Logs, l)
+			}
+
+			if len(result.Logs) == s.opts.count {
+				return &result, nil
+			}
+		}
+
+		if logs.NextPage == "" || len(logs.Logs) == 0 {
+			return &result, nil
+		}
+
+		q.Cursor = logs.NextPage
+	}
+}
+
+// Close flushes the output and closes the --output-file.
+func (s *searcher) Close() error {
+	if closer, ok := s.output.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+func (s *searcher) Run(ctx context.Context) error {
+	if s.opts.version {
+		_, err := fmt.Fprintln(s.output, version.Version)
+		return err
+	}
+
+	if err := s.resolveGroup(ctx); err != nil {
+		return err
+	}
+
+	q, err := s.query()
+	if err != nil {
+		return fmt.Errorf("error while preparing http request to SWO: %w", err)
+	}
+
+	logs, err := s.fetch(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	if s.opts.contextEnabled() {
+		return s.printWithContext(ctx, logs)
+	}
+
+	return s.printResult(logs)
+}
@@ -0,0 +1,219 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	statsCommandName = "stats"
+
+	byHostname = "hostname"
+	byProgram  = "program"
+	bySeverity = "severity"
+	byTime     = "time"
+
+	defaultStatsCount  = 1000
+	defaultStatsBucket = time.Hour
+)
+
+var (
+	errByFlag     = errors.New("unknown value of the by flag")
+	errBucketFlag = errors.New("--bucket must be positive")
+	errTopFlag    = errors.New("--top must not be negative")
+)
+
+type statsCommand struct {
+	fs     *flag.FlagSet
+	help   *commandHelp
+	search *searcher
+	opts   *Options
+
+	by     string
+	bucket time.Duration
+	top    int
+}
+
+type StatsGroup struct {
+	Value   string  `json:"value"`
+	Count   int     `json:"count"`
+	Percent float64 `json:"percent"`
+}
+
+type Stats struct {
+	By     string       `json:"by"`
+	Total  int          `json:"total"`
+	Groups []StatsGroup `json:"groups"`
+}
+
+func NewStatsCommand() *statsCommand {
+	help := newCommandHelp(statsCommandName, "count logs grouped by a field", "[--] [query]")
+	cmd := &statsCommand{
+		fs:   help.fs,
+		help: help,
+		opts: &Options{},
+	}
+
+	help.stringVar(&cmd.by, "by", byHostname, "FIELD", "Group by hostname, program, severity or time")
+	help.durationVar(&cmd.bucket, "bucket", defaultStatsBucket, "DURATION", "Bucket size when grouping by time").def = "1h"
+	help.intVar(&cmd.top, "top", 0, "NUMBER", "Show only the NUMBER largest groups").def = "all"
+	help.intVar(&cmd.opts.count, "count", defaultStatsCount, "NUMBER", "Number of log entries to analyze")
+	help.boolVar(&cmd.opts.json, "j,json", "Output JSON data")
+	registerSearchFlags(help, cmd.opts, "count")
+	registerParallelFlags(help, cmd.opts)
+	registerOutputFlags(help, cmd.opts)
+	registerConnectionFlags(help, cmd.opts)
+
+	help.example(
+		"swo-cli stats --min-time '1 minute ago'",
+		"swo-cli stats --by program --top 5 Failure",
+		"swo-cli stats --by time --bucket 5m --min-time '1 hour ago' -j",
+	)
+
+	return cmd
+}
+
+func (c *statsCommand) Init(args []string) error {
+	err := c.help.parse(args)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains([]string{byHostname, byProgram, bySeverity, byTime}, c.by) {
+		return errByFlag
+	}
+	if c.bucket <= 0 {
+		return errBucketFlag
+	}
+	if c.top < 0 {
+		return errTopFlag
+	}
+
+	opts, err := c.opts.Init(c.fs.Args())
+	if err != nil {
+		return err
+	}
+
+	search, err := newSearcher(opts)
+	if err != nil {
+		return err
+	}
+
+	c.search = search
+
+	return nil
+}
+
+func (c *statsCommand) Run(ctx context.Context) error {
+	if c.search == nil {
+		return fmt.Errorf("%s command was not initialized", statsCommandName)
+	}
+
+	err := c.run(ctx)
+
+	return errors.Join(err, c.search.Close())
+}
+
+func (c *statsCommand) run(ctx context.Context) error {
+	if err := c.search.resolveGroup(ctx); err != nil {
+		return err
+	}
+
+	q, err := c.search.query()
+	if err != nil {
+		return fmt.Errorf("error while preparing http request to SWO: %w", err)
+	}
+
+	logs, err := c.search.fetch(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	stats := computeStats(logs.Logs, c.by, c.bucket, c.top)
+	if c.opts.json {
+		jsonFormat, err := json.Marshal(stats)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(c.search.output, string(jsonFormat))
+		return err
+	}
+
+	for _, g := range stats.Groups {
+		_, err := fmt.Fprintf(c.search.output, "%7d %6.1f%% %s\n", g.Count, g.Percent, g.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *statsCommand) Name() string {
+	return statsCommandName
+}
+
+func (c *statsCommand) Usage() {
+	c.fs.Usage()
+}
+
+// computeStats counts logs grouped by the given field. Time buckets are
+// sorted chronologically, every other grouping by descending count.
+func computeStats(logs []Log, by string, bucket time.Duration, top int) *Stats {
+	counts := make(map[string]int)
+	for _, l := range logs {
+		counts[statsKey(l, by, bucket)]++
+	}
+
+	stats := &Stats{By: by, Total: len(logs), Groups: []StatsGroup{}}
+	for value, count := range counts {
+		stats.Groups = append(stats.Groups, StatsGroup{
+			Value:   value,
+			Count:   count,
+			Percent: float64(count) * 100 / float64(len(logs)),
+		})
+	}
+
+	slices.SortFunc(stats.Groups, func(a, b StatsGroup) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Value, b.Value)
+	})
+
+	if top > 0 && len(stats.Groups) > top {
+		stats.Groups = stats.Groups[:top]
+	}
+
+	if by == byTime {
+		slices.SortFunc(stats.Groups, func(a, b StatsGroup) int {
+			return strings.Compare(a.Value, b.Value)
+		})
+	}
+
+	return stats
+}
+
+func statsKey(l Log, by string, bucket time.Duration) string {
+	switch by {
+	case byProgram:
+		return l.Program
+	case bySeverity:
+		return l.Severity
+	case byTime:
+		return l.Time.Truncate(bucket).In(time.Local).Format(time.RFC3339)
+	default:
+		return l.Hostname
+	}
+}
+
+func (c *statsCommand) Help() *commandHelp {
+	return c.help
+}
@@ -0,0 +1,96 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeStats(t *testing.T) {
+	location, err := time.LoadLocation("GMT")
+	require.NoError(t, err)
+
+	time.Local = location
+
+	base, err := time.Parse(time.RFC3339, "2000-01-01T10:00:00Z")
+	require.NoError(t, err)
+
+	logs := []Log{
+		{Time: base, Hostname: "www42", Program: "nginx", Severity: "INFO"},
+		{Time: base.Add(10 * time.Minute), Hostname: "www42", Program: "nginx", Severity: "ERROR"},
+		{Time: base.Add(70 * time.Minute), Hostname: "acmedb-core01", Program: "pgsql", Severity: "INFO"},
+		{Time: base.Add(80 * time.Minute), Hostname: "www42", Program: "sshd", Severity: "INFO"},
+	}
+
+	testCases := []struct {
+		name     string
+		by       string
+		top      int
+		expected []StatsGroup
+	}{
+		{
+			name: "by hostname",
+			by:   byHostname,
+			expected: []StatsGroup{
+				{Value: "www42", Count: 3, Percent: 75},
+				{Value: "acmedb-core01", Count: 1, Percent: 25},
+			},
+		},
+		{
+			name: "by program with top",
+			by:   byProgram,
+			top:  2,
+			expected: []StatsGroup{
+				{Value: "nginx", Count: 2, Percent: 50},
+				{Value: "pgsql", Count: 1, Percent: 25},
+			},
+		},
+		{
+			name: "by severity",
+			by:   bySeverity,
+			expected: []StatsGroup{
+				{Value: "INFO", Count: 3, Percent: 75},
+				{Value: "ERROR", Count: 1, Percent: 25},
+			},
+		},
+		{
+			name: "by time",
+			by:   byTime,
+			expected: []StatsGroup{
+				{Value: "2000-01-01T10:00:00Z", Count: 2, Percent: 50},
+				{Value: "2000-01-01T11:00:00Z", Count: 2, Percent: 50},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stats := computeStats(logs, tc.by, time.Hour, tc.top)
+			require.Equal(t, tc.by, stats.By)
+			require.Equal(t, len(logs), stats.Total)
+			require.Equal(t, tc.expected, stats.Groups)
+		})
+	}
+}
+
+func TestStatsPages(t *testing.T) {
+	server, requests := newPagedServer(t, 2, 2)
+	createConfigFile(t, configFile, fmt.Sprintf("token: 1234567\napi-url: %s", server.URL))
+
+	cmd := NewStatsCommand()
+	require.NoError(t, cmd.Init([]string{"--configfile", configFile, "--count", "4", "-j"}))
+
+	var output bytes.Buffer
+	cmd.search.output = &output
+	require.NoError(t, cmd.run(context.Background()))
+	require.Equal(t, 2, *requests)
+
+	var stats Stats
+	require.NoError(t, json.Unmarshal(output.Bytes(), &stats))
+	require.Equal(t, 4, stats.Total)
+}
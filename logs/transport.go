@@ -0,0 +1,112 @@
+package logs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+const (
+	defaultTimeout     = time.Minute
+	defaultDialTimeout = 10 * time.Second
+)
+
+var (
+	errProxyFlag   = errors.New("failed to parse --proxy flag")
+	errCAFile      = errors.New("failed to load --ca-file")
+	errClientCert  = errors.New("failed to load client certificate")
+	errCertKeyPair = errors.New("--cert-file and --key-file must be provided together")
+)
+
+// newHTTPClient builds the http.Client used to talk to SWO from the
+// timeout, proxy and TLS settings found in flags and the config file.
+func newHTTPClient(opts *Options) (*http.Client, error) {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	dialTimeout := opts.DialTimeout
+	if dialTimeout == 0 {
+		dialTimeout = defaultDialTimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+
+	if opts.Proxy != "" {
+		proxyUrl, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, errors.Join(errProxyFlag, err)
+		}
+		if proxyUrl.Scheme == "" || proxyUrl.Host == "" {
+			return nil, fmt.Errorf("%w: %q is not an absolute URL", errProxyFlag, opts.Proxy)
+		}
+
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	}
+
+	tlsConfig, err := newTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}, nil
+}
+
+func newTLSConfig(opts *Options) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if opts.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		content, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, errors.Join(errCAFile, err)
+		}
+
+		if !pool.AppendCertsFromPEM(content) {
+			return nil, fmt.Errorf("%w: no PEM certificates found in %s", errCAFile, opts.CAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if (opts.CertFile == "") != (opts.KeyFile == "") {
+		return nil, errCertKeyPair
+	}
+
+	if opts.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, errors.Join(errClientCert, err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if opts.Insecure {
+		slog.Warn("TLS certificate verification is disabled")
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec // explicitly requested with --insecure
+	}
+
+	return tlsConfig, nil
+}
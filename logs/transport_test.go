@@ -0,0 +1,93 @@
+package logs
+
+import (
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	client, err := newHTTPClient(&Options{})
+	require.NoError(t, err)
+	require.Equal(t, defaultTimeout, client.Timeout)
+
+	client, err = newHTTPClient(&Options{Timeout: 5 * time.Second, Proxy: "http://proxy.example.com:3128"})
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, client.Timeout)
+
+	request, err := http.NewRequest("GET", "https://api.na-01.cloud.solarwinds.com/v1/logs", nil)
+	require.NoError(t, err)
+
+	proxyUrl, err := client.Transport.(*http.Transport).Proxy(request)
+	require.NoError(t, err)
+	require.Equal(t, &url.URL{Scheme: "http", Host: "proxy.example.com:3128"}, proxyUrl)
+
+	testCases := []struct {
+		name          string
+		opts          Options
+		expectedError error
+	}{
+		{
+			name:          "relative proxy",
+			opts:          Options{Proxy: "proxy.example.com"},
+			expectedError: errProxyFlag,
+		},
+		{
+			name:          "missing CA file",
+			opts:          Options{CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+			expectedError: errCAFile,
+		},
+		{
+			name:          "certificate without key",
+			opts:          Options{CertFile: "client.pem"},
+			expectedError: errCertKeyPair,
+		},
+		{
+			name:          "missing client certificate",
+			opts:          Options{CertFile: "missing.pem", KeyFile: "missing-key.pem"},
+			expectedError: errClientCert,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := newHTTPClient(&tc.opts)
+			require.True(t, errors.Is(err, tc.expectedError), "error: %v, expected: %v", err, tc.expectedError)
+		})
+	}
+}
+
+func TestHTTPClientTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := newHTTPClient(&Options{})
+	require.NoError(t, err)
+
+	_, err = client.Get(server.URL)
+	require.Error(t, err, "self-signed certificate should not be trusted by default")
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	err = os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600)
+	require.NoError(t, err)
+
+	for _, opts := range []Options{{CAFile: caFile}, {Insecure: true}} {
+		client, err := newHTTPClient(&opts)
+		require.NoError(t, err)
+
+		response, err := client.Get(server.URL)
+		require.NoError(t, err)
+		require.NoError(t, response.Body.Close())
+		require.Equal(t, http.StatusNoContent, response.StatusCode)
+	}
+}
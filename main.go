@@ -12,66 +12,64 @@ import (
 	"github.com/jskiba/papertrail-cli-poc/logs"
 )
 
-type Command interface {
-	Init([]string) error
-	Run(ctx context.Context) error
-	Name() string
-	Usage()
-}
-
-func showUsage(cmds []Command) {
-	fmt.Printf("\nUsage: %v %v\n\n", os.Args[0], "<command> [options]")
-	fmt.Printf("Commands:\n")
-	for _, cmd := range cmds {
-		cmd.Usage()
-	}
-}
-
 func main() {
-	cmds := []Command{
-		logs.NewLogsCommand(),
-	}
-
-	if len(os.Args[1:]) < 1 {
-		showUsage(cmds)
-		os.Exit(1)
-	}
-
-	for _, cmd := range cmds {
-		if cmd.Name() == os.Args[1] {
-			if err := cmd.Init(os.Args[2:]); err != nil {
-				if errors.Is(err, flag.ErrHelp) {
-					os.Exit(0)
-				}
+	registry := logs.NewRegistry()
+	logsCommand := logs.NewLogsCommand()
+	registry.Register(logsCommand)
+	registry.SetDefault(logsCommand)
+	registry.Register(logs.NewStatsCommand())
+	registry.Register(logs.NewHistogramCommand(), "hist")
+	registry.Register(logs.NewExportCommand())
+	registry.Register(logs.NewFormatCommand())
+	registry.Register(logs.NewExploreCommand())
+	registry.Register(logs.NewEntitiesCommand())
+	registry.Register(logs.NewGroupsCommand())
+	registry.Register(logs.NewCacheCommand())
+	registry.Register(logs.NewConfigShowCommand())
+	registry.Register(logs.NewHelpCommand(registry))
+	registry.Register(logs.NewCompletionCommand(registry))
 
-				slog.Error("Failed to initialize the command", slog.String("cmd", cmd.Name()), slog.String("error", err.Error()))
-				os.Exit(1)
-			}
+	cmd, err := registry.Init(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
 
-			ctx, cancel := context.WithCancel(context.Background())
-			c := make(chan os.Signal, 1)
-			signal.Notify(c, os.Interrupt)
-			defer func() {
-				signal.Stop(c)
-				cancel()
-			}()
-			go func() {
-				select {
-				case <-c:
-					cancel()
-				case <-ctx.Done():
-				}
-			}()
+		if errors.Is(err, logs.ErrNoCommand) {
+			registry.WriteOverview(os.Stdout)
+			os.Exit(1)
+		}
 
-			if err := cmd.Run(ctx); err != nil {
-				slog.Error("Failed to run the command", slog.String("cmd", cmd.Name()), slog.String("error", err.Error()))
-				os.Exit(1)
-			}
+		if cmd != nil {
+			slog.Error("Failed to initialize the command", slog.String("cmd", cmd.Name()), slog.String("error", err.Error()))
+		} else {
+			slog.Error("Failed to find the command", slog.String("error", err.Error()))
+		}
 
-			os.Exit(0)
+		var queryErr *logs.QueryError
+		if errors.As(err, &queryErr) {
+			fmt.Fprintln(os.Stderr, queryErr.Pointer())
 		}
+		os.Exit(1)
 	}
 
-	showUsage(cmds)
-	os.Exit(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	defer func() {
+		signal.Stop(c)
+		cancel()
+	}()
+	go func() {
+		select {
+		case <-c:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := cmd.Run(ctx); err != nil {
+		slog.Error("Failed to run the command", slog.String("cmd", cmd.Name()), slog.String("error", err.Error()))
+		os.Exit(1)
+	}
 }